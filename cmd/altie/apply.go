@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/copydataai/altie/internal/companions"
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/hooks"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/render"
+	"github.com/copydataai/altie/internal/themes"
+	cp "github.com/otiai10/copy"
+)
+
+var (
+	ErrNoCompanions       = errors.New("no companion is configured in the [Companions] table of altie.conf")
+	ErrIncompleteTemplate = errors.New("a template needs both a Source and a Target")
+	ErrNothingApplied     = errors.New("no theme was applied yet")
+)
+
+// applyTheme replaces the alacritty config with the named theme, keeping
+// the previous one as a backup, sets the configured font, renders the
+// templates of altie.conf and records the theme in the history. The
+// hooks of altie.conf run around it, their output going to w. The
+// picker and the TUI both apply through it.
+func applyTheme(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, state *config.State, name string) (string, error) {
+	path := filepath.Join(altieConfig.Config.ThemesDirectory, filepath.FromSlash(name))
+
+	// Read before anything changes, legacy YAML themes come back as TOML
+	// and a theme alacritty can't read stops the apply here
+	content, err := themes.ReadTheme(path)
+	if err != nil {
+		return "", err
+	}
+
+	applyHooks, err := hooks.Parse(altieConfig.Hooks)
+	if err != nil {
+		return "", err
+	}
+
+	// alacritty falls back to another font without a word, so a family
+	// that isn't installed stops the apply before the config is touched
+	err = checkConfiguredFont(w, altieConfig, appConfig)
+	if err != nil {
+		return "", err
+	}
+
+	var themePalette *palette.Palette
+	if len(altieConfig.Templates) > 0 || applyHooks.Configured() {
+		themePalette, err = themes.LoadPalette(path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	rendered, err := renderTemplates(altieConfig, appConfig, name, themePalette)
+	if err != nil {
+		return "", err
+	}
+
+	var env []string
+	if applyHooks.Configured() {
+		previous := ""
+		if recent := state.Recent(1); len(recent) == 1 {
+			previous = recent[0]
+		}
+
+		env = hooks.Env(name, path, previous, themePalette)
+	}
+
+	// Nothing changed yet, a failing PreApply hook only stops the apply
+	err = runHooks(w, applyHooks, "PreApply", applyHooks.PreApply, env)
+	if err != nil {
+		return "", err
+	}
+
+	previousOutputs, err := render.Snapshot(rendered)
+	if err != nil {
+		return "", err
+	}
+
+	backupTheme, err := themes.BackUpTheme(appConfig.AlacrittyConfig)
+	if err != nil {
+		return "", err
+	}
+
+	// From here on a failure puts back what the apply changed
+	err = os.WriteFile(appConfig.AlacrittyConfig, content, 0o644)
+	if err != nil {
+		return "", rollback(appConfig, backupTheme, previousOutputs, err)
+	}
+
+	err = themes.ApplyFontTheme(appConfig.AlacrittyConfig, &altieConfig.ThemeConfig)
+	if err != nil {
+		return "", rollback(appConfig, backupTheme, previousOutputs, err)
+	}
+
+	err = render.Save(rendered)
+	if err != nil {
+		return "", rollback(appConfig, backupTheme, previousOutputs, err)
+	}
+
+	err = runHooks(w, applyHooks, "PostApply", applyHooks.PostApply, env)
+	if err != nil {
+		return "", rollback(appConfig, backupTheme, previousOutputs, err)
+	}
+
+	state.AddHistory(name, time.Now())
+
+	return backupTheme, state.Save(appConfig.StatePath)
+}
+
+// runHooks runs commands and prints what they wrote. A failure is only
+// returned when the hooks roll back, otherwise it's printed as a warning.
+func runHooks(w io.Writer, applyHooks hooks.Hooks, stage string, commands []string, env []string) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	results, err := hooks.Run(context.Background(), commands, append(env, "ALTIE_HOOK="+stage), applyHooks.Timeout)
+	for _, result := range results {
+		if result.Output != "" {
+			fmt.Fprintf(w, "%s hook %q:\n%s\n", stage, result.Command, result.Output)
+		}
+	}
+
+	if err == nil || applyHooks.Policy == hooks.Rollback {
+		return err
+	}
+
+	fmt.Fprintf(w, "Warning: %s %s\n", stage, err)
+
+	return nil
+}
+
+// rollback puts back the alacritty config and the template outputs from
+// before the apply after a step of it or a hook failed.
+func rollback(appConfig *config.AppConfig, backupTheme string, previousOutputs []render.Rendered, cause error) error {
+	err := cp.Copy(backupTheme, appConfig.AlacrittyConfig)
+	if err != nil {
+		return fmt.Errorf("%w, restoring %s failed too: %v", cause, backupTheme, err)
+	}
+
+	err = render.Save(previousOutputs)
+	if err != nil {
+		return fmt.Errorf("%w, restoring the templates failed too: %v", cause, err)
+	}
+
+	return fmt.Errorf("%w, the previous theme was put back", cause)
+}
+
+// renderTemplates renders the [[Templates]] of altie.conf with the palette
+// of the theme. They're rendered before anything is applied, so a broken
+// template stops the apply and leaves every file as it was.
+func renderTemplates(altieConfig *config.ConfigThemes, appConfig *config.AppConfig, name string, themePalette *palette.Palette) ([]render.Rendered, error) {
+	if len(altieConfig.Templates) == 0 {
+		return nil, nil
+	}
+
+	files := make([]render.File, 0, len(altieConfig.Templates))
+	for _, tmpl := range altieConfig.Templates {
+		if tmpl.Source == "" || tmpl.Target == "" {
+			return nil, fmt.Errorf("%w: %+v", ErrIncompleteTemplate, tmpl)
+		}
+
+		source := config.ExpandHome(tmpl.Source, appConfig.HomeDir)
+		parsed, err := render.ParseFile(source)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, render.File{Name: source, Template: parsed, Path: config.ExpandHome(tmpl.Target, appConfig.HomeDir)})
+	}
+
+	return render.Render(files, render.NewData(exportName(name), themePalette))
+}
+
+// templateTargets are the files the [[Templates]] of altie.conf render
+// into, without their content.
+func templateTargets(altieConfig *config.ConfigThemes, appConfig *config.AppConfig) []render.Rendered {
+	targets := make([]render.Rendered, 0, len(altieConfig.Templates))
+	for _, tmpl := range altieConfig.Templates {
+		if tmpl.Target != "" {
+			targets = append(targets, render.Rendered{Path: config.ExpandHome(tmpl.Target, appConfig.HomeDir)})
+		}
+	}
+
+	return targets
+}
+
+// renderCompanions renders the palette of the named theme into the files
+// of the companions configured in altie.conf.
+func renderCompanions(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, name string) error {
+	outputs := companions.Outputs(altieConfig.Companions, appConfig.HomeDir)
+	if len(outputs) == 0 {
+		return ErrNoCompanions
+	}
+
+	themePalette, err := themes.LoadPalette(filepath.Join(altieConfig.Config.ThemesDirectory, filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+
+	err = companions.Write(outputs, render.NewData(exportName(name), themePalette))
+	if err != nil {
+		return err
+	}
+
+	for _, output := range outputs {
+		fmt.Fprintf(w, "Rendered %s for %s\n", output.Path, output.Tool)
+	}
+
+	return nil
+}
+
+// Apply applies theme and, with withCompanions, renders it for the
+// configured companions. Without a theme the companions are rendered for
+// the last applied one, leaving alacritty as it is.
+func Apply(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, theme string, withCompanions bool) error {
+	state, err := config.LoadState(appConfig.StatePath)
+	if err != nil {
+		return err
+	}
+
+	var name string
+	if theme == "" {
+		recent := state.Recent(1)
+		if len(recent) == 0 {
+			return ErrNothingApplied
+		}
+
+		name = recent[0]
+	} else {
+		name, err = themeName(altieConfig, theme)
+		if err != nil {
+			return err
+		}
+
+		_, err = applyTheme(w, altieConfig, appConfig, state, name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "Applied %s\n", name)
+	}
+
+	if !withCompanions {
+		return nil
+	}
+
+	return renderCompanions(w, altieConfig, appConfig, name)
+}
+
+func runApply(args []string) error {
+	flags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	withCompanions := flags.Bool("companions", false, "also render the theme for the companions configured in altie.conf")
+
+	names, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(names) > 1 || (len(names) == 0 && !*withCompanions) {
+		return fmt.Errorf("%w: altie apply [--companions] <theme>", ErrMissingTheme)
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	theme := ""
+	if len(names) == 1 {
+		theme = names[0]
+	}
+
+	return Apply(os.Stdout, altieConfig, appConfig, theme, *withCompanions)
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/hooks"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+
+	c.ErrorIs(Apply(&bytes.Buffer{}, altieConfig, appConfig, "", true), ErrNothingApplied)
+
+	out := &bytes.Buffer{}
+	c.NoError(Apply(out, altieConfig, appConfig, "Nord", false))
+	c.Equal("Applied Nord.toml\n", out.String())
+
+	c.ErrorIs(Apply(&bytes.Buffer{}, altieConfig, appConfig, "", true), ErrNoCompanions)
+
+	altieConfig.Companions = config.Companions{
+		Tmux:   "~/.config/tmux/altie.conf",
+		Neovim: filepath.Join(tmpDir, "nvim", "colors", "altie.lua"),
+	}
+
+	// Without a theme the companions follow the last applied one
+	out.Reset()
+	c.NoError(Apply(out, altieConfig, appConfig, "", true))
+	c.Equal("Rendered "+filepath.Join(tmpDir, ".config", "tmux", "altie.conf")+" for tmux\n"+
+		"Rendered "+filepath.Join(tmpDir, "nvim", "colors", "altie.lua")+" for nvim\n", out.String())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "nvim", "colors", "altie.lua"))
+	c.NoError(err)
+	c.Contains(string(content), "-- Nord, generated by altie")
+
+	out.Reset()
+	c.NoError(Apply(out, altieConfig, appConfig, "Solarized-Light", true))
+	c.Contains(out.String(), "Applied Solarized-Light.toml\n")
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "nvim", "colors", "altie.lua"))
+	c.NoError(err)
+	c.Contains(string(content), `vim.o.background = "light"`)
+}
+
+func TestApplyLegacyTheme(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+
+	legacy := `colors:
+  primary:
+    background: '0x2E3440'
+    foreground: '0xd8dee9'
+  normal:
+    black:   '0x3b4252'
+    red:     '0xbf616a'
+    green:   '0xa3be8c'
+    yellow:  '0xebcb8b'
+    blue:    '0x81a1c1'
+    magenta: '0xb48ead'
+    cyan:    '0x88c0d0'
+    white:   '0xe5e9f0'
+  bright:
+    black:   '0x4c566a'
+    red:     '0xbf616a'
+    green:   '0xa3be8c'
+    yellow:  '0xebcb8b'
+    blue:    '0x81a1c1'
+    magenta: '0xb48ead'
+    cyan:    '0x8fbcbb'
+    white:   '0xeceff4'
+`
+	c.NoError(os.WriteFile(filepath.Join(appConfig.ThemesDir, "Nord.yml"), []byte(legacy), 0o644))
+
+	c.NoError(Apply(&bytes.Buffer{}, altieConfig, appConfig, "Nord.yml", false))
+
+	alacritty, err := themes.CheckAlacrittyConfig(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	colors := alacritty["colors"].(map[string]any)
+	c.Equal(map[string]any{"background": "#2e3440", "foreground": "#d8dee9"}, colors["primary"])
+	c.Equal("monospace", alacritty["font"].(map[string]any)["normal"].(map[string]any)["family"])
+
+	// A theme alacritty can't read is refused before the config is touched
+	c.NoError(os.WriteFile(filepath.Join(appConfig.ThemesDir, "Broken.yml"), []byte("colors: [unclosed\n"), 0o644))
+
+	before, err := os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+
+	c.ErrorIs(Apply(&bytes.Buffer{}, altieConfig, appConfig, "Broken.yml", false), themes.ErrInvalidTheme)
+
+	after, err := os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Equal(before, after)
+
+	backups, err := filepath.Glob(appConfig.AlacrittyConfig + ".*.bak")
+	c.NoError(err)
+	c.Len(backups, 1)
+}
+
+func TestApplyTemplates(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+
+	source := filepath.Join(tmpDir, "waybar.css.tmpl")
+	c.NoError(os.WriteFile(source, []byte("@define-color bg {{hex .Background}};\n@define-color accent {{lighten .Colors.blue 0.1 | hex}};\n"), 0o644))
+
+	altieConfig.Templates = []config.Template{{Source: source, Target: "~/.config/waybar/colors.css"}}
+	target := filepath.Join(tmpDir, ".config", "waybar", "colors.css")
+
+	c.NoError(Apply(&bytes.Buffer{}, altieConfig, appConfig, "Nord", false))
+
+	content, err := os.ReadFile(target)
+	c.NoError(err)
+	c.Equal("@define-color bg #2e3440;\n@define-color accent #a3bbd2;\n", string(content))
+
+	// A broken template stops the apply before anything changed
+	alacritty, err := os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+
+	altieConfig.Templates = append(altieConfig.Templates, config.Template{Source: source + ".broken", Target: filepath.Join(tmpDir, "broken")})
+	c.NoError(os.WriteFile(source+".broken", []byte("{{hex .Colors.purple}}"), 0o644))
+
+	err = Apply(&bytes.Buffer{}, altieConfig, appConfig, "Solarized-Light", false)
+	c.ErrorContains(err, `map has no entry for key "purple"`)
+
+	content, err = os.ReadFile(target)
+	c.NoError(err)
+	c.Contains(string(content), "#2e3440")
+
+	current, err := os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Equal(alacritty, current)
+
+	state, err := config.LoadState(appConfig.StatePath)
+	c.NoError(err)
+	c.Equal([]string{"Nord.toml"}, state.Recent(5))
+
+	altieConfig.Templates = []config.Template{{Source: source}}
+	c.ErrorIs(Apply(&bytes.Buffer{}, altieConfig, appConfig, "Nord", false), ErrIncompleteTemplate)
+}
+
+func TestApplyHooks(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+
+	altieConfig.Hooks = config.Hooks{
+		PreApply:  []string{`echo "$ALTIE_HOOK $ALTIE_THEME after '$ALTIE_PREVIOUS_THEME'"`},
+		PostApply: []string{`echo "$ALTIE_HOOK $ALTIE_VARIANT $ALTIE_BG"`},
+	}
+
+	out := &bytes.Buffer{}
+	c.NoError(Apply(out, altieConfig, appConfig, "Nord", false))
+	c.Equal("PreApply hook \"echo \\\"$ALTIE_HOOK $ALTIE_THEME after '$ALTIE_PREVIOUS_THEME'\\\"\":\n"+
+		"PreApply Nord.toml after ''\n"+
+		"PostApply hook \"echo \\\"$ALTIE_HOOK $ALTIE_VARIANT $ALTIE_BG\\\"\":\n"+
+		"PostApply dark #2e3440\n"+
+		"Applied Nord.toml\n", out.String())
+
+	// A failing hook only warns by default
+	altieConfig.Hooks = config.Hooks{PostApply: []string{"exit 1"}}
+	out.Reset()
+	c.NoError(Apply(out, altieConfig, appConfig, "Solarized-Light", false))
+	c.Equal("Warning: PostApply hook failed: exit 1: exit status 1\nApplied Solarized-Light.toml\n", out.String())
+
+	// Or puts the previous theme and templates back
+	source := filepath.Join(tmpDir, "bg.tmpl")
+	target := filepath.Join(tmpDir, "bg.txt")
+	c.NoError(os.WriteFile(source, []byte("{{hex .Background}}"), 0o644))
+	altieConfig.Templates = []config.Template{{Source: source, Target: target}}
+	altieConfig.Hooks = config.Hooks{PostApply: []string{"sleep 5"}, Timeout: "100ms", OnFailure: "rollback"}
+
+	alacritty, err := os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+
+	err = Apply(&bytes.Buffer{}, altieConfig, appConfig, "Nord", false)
+	c.ErrorIs(err, hooks.ErrHookFailed)
+	c.EqualError(err, "hook failed: sleep 5: timed out after 100ms, the previous theme was put back")
+	c.NoFileExists(target)
+
+	current, err := os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Equal(alacritty, current)
+
+	state, err := config.LoadState(appConfig.StatePath)
+	c.NoError(err)
+	c.Equal([]string{"Solarized-Light.toml", "Nord.toml"}, state.Recent(5))
+
+	// A template that can't be saved puts everything back too, the hooks
+	// don't run
+	outputs := filepath.Join(tmpDir, "out")
+	altieConfig.Templates = []config.Template{
+		{Source: source, Target: filepath.Join(outputs, "bg.txt")},
+		{Source: source, Target: outputs},
+	}
+	altieConfig.Hooks = config.Hooks{PostApply: []string{"exit 1"}, OnFailure: "rollback"}
+
+	err = Apply(&bytes.Buffer{}, altieConfig, appConfig, "Solarized-Light", false)
+	c.ErrorContains(err, "the previous theme was put back")
+	c.NotErrorIs(err, hooks.ErrHookFailed)
+	c.NoFileExists(filepath.Join(outputs, "bg.txt"))
+	c.NoDirExists(outputs)
+
+	current, err = os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Equal(alacritty, current)
+
+	altieConfig.Templates = []config.Template{{Source: source, Target: target}}
+	altieConfig.Hooks = config.Hooks{PreApply: []string{"exit 2"}, OnFailure: "rollback"}
+	c.ErrorIs(Apply(&bytes.Buffer{}, altieConfig, appConfig, "Nord", false), hooks.ErrHookFailed)
+	c.NoFileExists(target)
+
+	altieConfig.Hooks = config.Hooks{OnFailure: "ignore"}
+	c.ErrorIs(Apply(&bytes.Buffer{}, altieConfig, appConfig, "Nord", false), hooks.ErrInvalidPolicy)
+}
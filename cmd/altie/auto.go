@@ -0,0 +1,228 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/schedule"
+)
+
+var ErrMixedSchedule = errors.New("a schedule uses either fixed times or a location, not both")
+
+// ShowSchedule prints the schedule and which theme it wants at now.
+func ShowSchedule(w io.Writer, cfg config.Schedule, now time.Time) error {
+	if cfg.Mode == "" && (cfg.LightTheme != "" || cfg.DarkTheme != "") {
+		fmt.Fprintln(w, "Mode: none, only altie follow-system switches themes")
+		fmt.Fprintf(w, "Light theme: %s\n", cfg.LightTheme)
+		fmt.Fprintf(w, "Dark theme: %s\n", cfg.DarkTheme)
+
+		return nil
+	}
+
+	s, err := schedule.Parse(cfg)
+	if err != nil {
+		return err
+	}
+
+	mode := s.Mode
+	if s.Mode == schedule.ModeSolar {
+		mode = fmt.Sprintf("%s, at %.4f, %.4f", s.Mode, s.Latitude, s.Longitude)
+	}
+
+	fmt.Fprintf(w, "Mode: %s\n", mode)
+	fmt.Fprintf(w, "Light theme: %s\n", s.LightTheme)
+	fmt.Fprintf(w, "Dark theme: %s\n", s.DarkTheme)
+
+	lightAt, darkAt, allDay := s.Transitions(now)
+	if allDay != "" {
+		fmt.Fprintf(w, "Today: %s all day\n", allDay)
+	} else {
+		fmt.Fprintf(w, "Today: light at %s, dark at %s\n", lightAt.Format("15:04"), darkAt.Format("15:04"))
+	}
+
+	variant, next := s.At(now)
+	fmt.Fprintf(w, "Now: %s until %s\n", variant, next.Format(time.DateTime))
+
+	return nil
+}
+
+// applyUnlessCurrent applies theme unless it's already the last applied
+// one and force isn't set, and returns its name and whether it applied it.
+func applyUnlessCurrent(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, theme string, force bool) (string, bool, error) {
+	name, err := themeName(altieConfig, theme)
+	if err != nil {
+		return "", false, err
+	}
+
+	state, err := config.LoadState(appConfig.StatePath)
+	if err != nil {
+		return "", false, err
+	}
+
+	if recent := state.Recent(1); !force && len(recent) == 1 && recent[0] == name {
+		return name, false, nil
+	}
+
+	_, err = applyTheme(w, altieConfig, appConfig, state, name)
+	if err != nil {
+		return "", false, err
+	}
+
+	return name, true, nil
+}
+
+// Auto applies the theme the schedule wants at now, unless it's already
+// the last applied one and force isn't set, and returns its name.
+func Auto(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, now time.Time, force bool) (string, error) {
+	s, err := schedule.Parse(altieConfig.Schedule)
+	if err != nil {
+		return "", err
+	}
+
+	variant, next := s.At(now)
+
+	name, applied, err := applyUnlessCurrent(w, altieConfig, appConfig, s.Theme(variant), force)
+	if err != nil {
+		return "", err
+	}
+
+	if !applied {
+		fmt.Fprintf(w, "%s is already applied until %s\n", name, next.Format(time.DateTime))
+		return name, nil
+	}
+
+	fmt.Fprintf(w, "Applied %s until %s\n", name, next.Format(time.DateTime))
+
+	return name, nil
+}
+
+// scheduleFlags are the settings given to altie schedule, set tells
+// which flags were used.
+type scheduleFlags struct {
+	light, dark         string
+	lightAt, darkAt     string
+	latitude, longitude float64
+	set                 map[string]bool
+}
+
+// update changes the schedule with the flags that were set. Fixed times
+// switch it to the fixed mode, a location to the solar one.
+func (f scheduleFlags) update(cfg config.Schedule) (config.Schedule, error) {
+	fixed, solar := f.set["light-at"] || f.set["dark-at"], f.set["lat"] || f.set["lon"]
+	if fixed && solar {
+		return cfg, ErrMixedSchedule
+	}
+
+	if f.set["light"] {
+		cfg.LightTheme = f.light
+	}
+
+	if f.set["dark"] {
+		cfg.DarkTheme = f.dark
+	}
+
+	switch {
+	case fixed:
+		cfg.Mode, cfg.Latitude, cfg.Longitude = schedule.ModeFixed, 0, 0
+		if f.set["light-at"] {
+			cfg.LightAt = f.lightAt
+		}
+
+		if f.set["dark-at"] {
+			cfg.DarkAt = f.darkAt
+		}
+	case solar:
+		cfg.Mode, cfg.LightAt, cfg.DarkAt = schedule.ModeSolar, "", ""
+		if f.set["lat"] {
+			cfg.Latitude = f.latitude
+		}
+
+		if f.set["lon"] {
+			cfg.Longitude = f.longitude
+		}
+	}
+
+	// The themes alone are enough for altie follow-system
+	if cfg.Mode == "" {
+		return cfg, nil
+	}
+
+	_, err := schedule.Parse(cfg)
+
+	return cfg, err
+}
+
+func runSchedule(args []string) error {
+	flags := flag.NewFlagSet("schedule", flag.ContinueOnError)
+	options := scheduleFlags{set: make(map[string]bool)}
+	flags.StringVar(&options.light, "light", "", "theme applied during the day")
+	flags.StringVar(&options.dark, "dark", "", "theme applied during the night")
+	flags.StringVar(&options.lightAt, "light-at", "", "time the light theme is applied at, as HH:MM")
+	flags.StringVar(&options.darkAt, "dark-at", "", "time the dark theme is applied at, as HH:MM")
+	flags.Float64Var(&options.latitude, "lat", 0, "latitude to apply the light theme from sunrise to sunset")
+	flags.Float64Var(&options.longitude, "lon", 0, "longitude to apply the light theme from sunrise to sunset")
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		options.set[f.Name] = true
+	})
+
+	if len(options.set) == 0 {
+		return ShowSchedule(os.Stdout, altieConfig.Schedule, time.Now())
+	}
+
+	for _, theme := range []*string{&options.light, &options.dark} {
+		if *theme == "" {
+			continue
+		}
+
+		*theme, err = themeName(altieConfig, *theme)
+		if err != nil {
+			return err
+		}
+	}
+
+	altieConfig.Schedule, err = options.update(altieConfig.Schedule)
+	if err != nil {
+		return err
+	}
+
+	err = altieConfig.Save(appConfig.ConfigFilePath)
+	if err != nil {
+		return err
+	}
+
+	return ShowSchedule(os.Stdout, altieConfig.Schedule, time.Now())
+}
+
+func runAuto(args []string) error {
+	flags := flag.NewFlagSet("auto", flag.ContinueOnError)
+	force := flags.Bool("force", false, "apply the theme even when it's already the last applied one")
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	_, err = Auto(os.Stdout, altieConfig, appConfig, time.Now(), *force)
+	return err
+}
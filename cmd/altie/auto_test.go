@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/schedule"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleFlags(t *testing.T) {
+	c := require.New(t)
+
+	options := scheduleFlags{
+		light:   "Solarized-Light.toml",
+		dark:    "Nord.toml",
+		lightAt: "07:00",
+		darkAt:  "19:30",
+		set:     map[string]bool{"light": true, "dark": true, "light-at": true, "dark-at": true},
+	}
+
+	cfg, err := options.update(config.Schedule{})
+	c.NoError(err)
+	c.Equal(config.Schedule{
+		Mode:       schedule.ModeFixed,
+		LightTheme: "Solarized-Light.toml",
+		DarkTheme:  "Nord.toml",
+		LightAt:    "07:00",
+		DarkAt:     "19:30",
+	}, cfg)
+
+	// A location switches to sunrise and sunset, keeping the themes
+	options = scheduleFlags{latitude: 51.5, longitude: -0.13, set: map[string]bool{"lat": true, "lon": true}}
+	cfg, err = options.update(cfg)
+	c.NoError(err)
+	c.Equal(config.Schedule{
+		Mode:       schedule.ModeSolar,
+		LightTheme: "Solarized-Light.toml",
+		DarkTheme:  "Nord.toml",
+		Latitude:   51.5,
+		Longitude:  -0.13,
+	}, cfg)
+
+	options = scheduleFlags{darkAt: "20:00", latitude: 10, set: map[string]bool{"dark-at": true, "lat": true}}
+	_, err = options.update(cfg)
+	c.ErrorIs(err, ErrMixedSchedule)
+
+	// The themes alone are kept for altie follow-system
+	options = scheduleFlags{light: "Solarized-Light.toml", dark: "Nord.toml", set: map[string]bool{"light": true, "dark": true}}
+	cfg, err = options.update(config.Schedule{})
+	c.NoError(err)
+	c.Equal(config.Schedule{LightTheme: "Solarized-Light.toml", DarkTheme: "Nord.toml"}, cfg)
+
+	options = scheduleFlags{darkAt: "8pm", set: map[string]bool{"dark-at": true}}
+	_, err = options.update(cfg)
+	c.ErrorIs(err, schedule.ErrInvalidTime)
+}
+
+func TestShowSchedule(t *testing.T) {
+	c := require.New(t)
+
+	cfg := config.Schedule{
+		Mode:       schedule.ModeFixed,
+		LightTheme: "Solarized-Light.toml",
+		DarkTheme:  "Nord.toml",
+		LightAt:    "07:00",
+		DarkAt:     "19:30",
+	}
+
+	out := &bytes.Buffer{}
+	c.NoError(ShowSchedule(out, cfg, time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)))
+	c.Equal("Mode: fixed\n"+
+		"Light theme: Solarized-Light.toml\n"+
+		"Dark theme: Nord.toml\n"+
+		"Today: light at 07:00, dark at 19:30\n"+
+		"Now: light until 2024-05-10 19:30:00\n", out.String())
+
+	out.Reset()
+	c.NoError(ShowSchedule(out, config.Schedule{LightTheme: "Solarized-Light.toml", DarkTheme: "Nord.toml"}, time.Now()))
+	c.Equal("Mode: none, only altie follow-system switches themes\n"+
+		"Light theme: Solarized-Light.toml\n"+
+		"Dark theme: Nord.toml\n", out.String())
+
+	c.ErrorIs(ShowSchedule(out, config.Schedule{}, time.Now()), schedule.ErrNoSchedule)
+}
+
+// setupSchedule creates altie.conf and the alacritty config in tmpDir,
+// with the Nord and Solarized Light themes to switch between.
+func setupSchedule(c *require.Assertions, tmpDir string) (*config.ConfigThemes, *config.AppConfig) {
+	appConfig := config.NewAppConfig(tmpDir)
+	c.NoError(config.CreateConfig(appConfig))
+	c.NoError(os.MkdirAll(appConfig.ThemesDir, os.ModePerm))
+	c.NoError(os.MkdirAll(appConfig.AlacrittyDir, os.ModePerm))
+	c.NoError(os.WriteFile(appConfig.AlacrittyConfig, nil, 0o644))
+
+	for _, name := range []string{"Nord.toml", "Solarized-Light.toml"} {
+		content, err := os.ReadFile(filepath.Join("..", "..", "themes", name))
+		c.NoError(err)
+		c.NoError(os.WriteFile(filepath.Join(appConfig.ThemesDir, name), content, 0o644))
+	}
+
+	altieConfig, err := config.CheckConfig(appConfig.ConfigFilePath)
+	c.NoError(err)
+
+	return altieConfig, appConfig
+}
+
+func TestAuto(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+
+	_, err = Auto(&bytes.Buffer{}, altieConfig, appConfig, time.Now(), false)
+	c.ErrorIs(err, schedule.ErrNoSchedule)
+
+	altieConfig.Schedule = config.Schedule{
+		Mode:       schedule.ModeFixed,
+		LightTheme: "Solarized-Light",
+		DarkTheme:  "Nord",
+		LightAt:    "07:00",
+		DarkAt:     "19:30",
+	}
+
+	out := &bytes.Buffer{}
+	name, err := Auto(out, altieConfig, appConfig, time.Date(2024, 5, 10, 22, 0, 0, 0, time.UTC), false)
+	c.NoError(err)
+	c.Equal("Nord.toml", name)
+	c.Equal("Applied Nord.toml until 2024-05-11 07:00:00\n", out.String())
+
+	// Running it again in the same period doesn't apply the theme twice
+	out.Reset()
+	_, err = Auto(out, altieConfig, appConfig, time.Date(2024, 5, 11, 6, 0, 0, 0, time.UTC), false)
+	c.NoError(err)
+	c.Equal("Nord.toml is already applied until 2024-05-11 07:00:00\n", out.String())
+
+	name, err = Auto(out, altieConfig, appConfig, time.Date(2024, 5, 11, 9, 0, 0, 0, time.UTC), false)
+	c.NoError(err)
+	c.Equal("Solarized-Light.toml", name)
+
+	_, err = Auto(out, altieConfig, appConfig, time.Date(2024, 5, 11, 9, 5, 0, 0, time.UTC), true)
+	c.NoError(err)
+
+	state, err := config.LoadState(appConfig.StatePath)
+	c.NoError(err)
+	c.Len(state.History, 3)
+}
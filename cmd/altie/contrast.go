@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/pterm/pterm"
+)
+
+var ErrMissingTheme = errors.New("missing theme name")
+
+type contrastOptions struct {
+	minContrast float64
+	apca        bool
+	minAPCA     float64
+}
+
+// Contrast prints the contrast of every color pair of a theme and returns
+// how many of them fail the requested minimums.
+func Contrast(w io.Writer, themePath string, options contrastOptions) (int, error) {
+	themePalette, err := themes.LoadPalette(themePath)
+	if err != nil {
+		return 0, err
+	}
+
+	header := []string{"Pair", "Foreground", "Background", "Ratio"}
+	if options.apca {
+		header = append(header, "APCA Lc")
+	}
+	header = append(header, "Result")
+
+	data := [][]string{header}
+	failing := 0
+	pairs := themePalette.Pairs()
+	for _, pair := range pairs {
+		ratio := pair.Ratio()
+		passes := ratio >= options.minContrast
+
+		row := []string{pair.Name, pair.Foreground.Hex(), pair.Background.Hex(), fmt.Sprintf("%.2f:1", ratio)}
+		if options.apca {
+			lc := pair.APCA()
+			passes = passes && math.Abs(lc) >= options.minAPCA
+			row = append(row, fmt.Sprintf("%.1f", lc))
+		}
+
+		if passes {
+			row = append(row, pterm.Green("pass"))
+		} else {
+			failing++
+			row = append(row, pterm.Red("FAIL"))
+		}
+
+		data = append(data, row)
+	}
+
+	err = pterm.DefaultTable.WithHasHeader().WithWriter(w).WithData(data).Render()
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(w, "%d of %d pairs are below %.1f:1", failing, len(pairs), options.minContrast)
+	if options.apca {
+		fmt.Fprintf(w, " or Lc %.0f", options.minAPCA)
+	}
+	fmt.Fprintln(w)
+
+	return failing, nil
+}
+
+func runContrast(args []string) error {
+	flags := flag.NewFlagSet("contrast", flag.ContinueOnError)
+	options := contrastOptions{}
+	flags.Float64Var(&options.minContrast, "min-contrast", palette.WCAGAA, "minimum WCAG contrast ratio")
+	flags.BoolVar(&options.apca, "apca", false, "also check the APCA lightness contrast")
+	flags.Float64Var(&options.minAPCA, "min-apca", palette.APCABody, "minimum APCA Lc when --apca is set")
+
+	names, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(names) != 1 {
+		return fmt.Errorf("%w: altie contrast [flags] <theme>", ErrMissingTheme)
+	}
+
+	altieConfig, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	themePath, err := themes.ResolveTheme(altieConfig.Config.ThemesDirectory, names[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = Contrast(os.Stdout, themePath, options)
+	return err
+}
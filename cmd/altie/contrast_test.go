@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContrast(t *testing.T) {
+	c := require.New(t)
+
+	nord := filepath.Join("..", "..", "themes", "Nord.toml")
+
+	out := &bytes.Buffer{}
+	failing, err := Contrast(out, nord, contrastOptions{minContrast: 4.5})
+	c.NoError(err)
+	c.Contains(out.String(), "normal black")
+	c.Contains(out.String(), "#d8dee9")
+	c.NotContains(out.String(), "APCA")
+	c.Contains(out.String(), "pairs are below 4.5:1")
+
+	// Nord's blacks sit almost on its background
+	c.Greater(failing, 0)
+
+	out.Reset()
+	none, err := Contrast(out, nord, contrastOptions{minContrast: 1})
+	c.NoError(err)
+	c.Zero(none)
+
+	out.Reset()
+	withAPCA, err := Contrast(out, nord, contrastOptions{minContrast: 1, apca: true, minAPCA: 60})
+	c.NoError(err)
+	c.Contains(out.String(), "APCA Lc")
+	c.Contains(out.String(), "or Lc 60")
+	c.Greater(withAPCA, 0)
+
+	_, err = Contrast(out, "NonExisting.toml", contrastOptions{})
+	c.Error(err)
+}
+
+func TestRunContrastArgs(t *testing.T) {
+	c := require.New(t)
+
+	err := run([]string{"contrast"})
+	c.ErrorIs(err, ErrMissingTheme)
+
+	err = run([]string{"contrast", "--min-contrast", "x", "Nord"})
+	c.Error(err)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/pterm/pterm"
+)
+
+type cvdOptions struct {
+	deficiency palette.Deficiency
+	threshold  float64
+}
+
+func swatch(color palette.Color) string {
+	return pterm.NewRGB(color.R, color.G, color.B, true).Sprint("      ") + " " + color.Hex()
+}
+
+// CVD prints the palette of a theme as seen with a color vision deficiency
+// and returns the ANSI color pairs that become hard to tell apart.
+func CVD(w io.Writer, themePath string, options cvdOptions) ([]palette.Confusion, error) {
+	themePalette, err := themes.LoadPalette(themePath)
+	if err != nil {
+		return nil, err
+	}
+
+	simulated := themePalette.Simulate(options.deficiency)
+
+	data := [][]string{
+		{"Color", "Original", string(options.deficiency)},
+		{"background", swatch(themePalette.Background), swatch(simulated.Background)},
+		{"foreground", swatch(themePalette.Foreground), swatch(simulated.Foreground)},
+	}
+
+	simulatedANSI := simulated.ANSI()
+	for i, named := range themePalette.ANSI() {
+		data = append(data, []string{named.Name, swatch(named.Color), swatch(simulatedANSI[i].Color)})
+	}
+
+	err = pterm.DefaultTable.WithHasHeader().WithWriter(w).WithData(data).Render()
+	if err != nil {
+		return nil, err
+	}
+
+	confusions := themePalette.Confusions(options.deficiency, options.threshold)
+	if len(confusions) == 0 {
+		fmt.Fprintf(w, "\nNo ANSI colors fall under ΔE %.1f with %s\n", options.threshold, options.deficiency)
+		return confusions, nil
+	}
+
+	fmt.Fprintln(w)
+
+	data = [][]string{{"Pair", "ΔE before", "ΔE after"}}
+	for _, confusion := range confusions {
+		data = append(data, []string{
+			confusion.First + " / " + confusion.Second,
+			fmt.Sprintf("%.1f", confusion.Before),
+			pterm.Red(fmt.Sprintf("%.1f", confusion.After)),
+		})
+	}
+
+	err = pterm.DefaultTable.WithHasHeader().WithWriter(w).WithData(data).Render()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(w, "%d ANSI color pairs fall under ΔE %.1f with %s\n", len(confusions), options.threshold, options.deficiency)
+
+	return confusions, nil
+}
+
+func runCVD(args []string) error {
+	flags := flag.NewFlagSet("cvd", flag.ContinueOnError)
+	options := cvdOptions{}
+	deficiency := flags.String("type", string(palette.Deuteranopia), "protanopia, deuteranopia or tritanopia")
+	flags.Float64Var(&options.threshold, "threshold", palette.Distinguishable, "CIEDE2000 difference under which colors look alike")
+
+	names, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(names) != 1 {
+		return fmt.Errorf("%w: altie cvd [flags] <theme>", ErrMissingTheme)
+	}
+
+	options.deficiency, err = palette.ParseDeficiency(*deficiency)
+	if err != nil {
+		return err
+	}
+
+	altieConfig, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	themePath, err := themes.ResolveTheme(altieConfig.Config.ThemesDirectory, names[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = CVD(os.Stdout, themePath, options)
+	return err
+}
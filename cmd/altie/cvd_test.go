@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCVD(t *testing.T) {
+	c := require.New(t)
+
+	dracula := filepath.Join("..", "..", "themes", "Dracula.toml")
+
+	out := &bytes.Buffer{}
+	confusions, err := CVD(out, dracula, cvdOptions{deficiency: palette.Deuteranopia, threshold: palette.Distinguishable})
+	c.NoError(err)
+	c.NotEmpty(confusions)
+	c.Contains(out.String(), "deuteranopia")
+	c.Contains(out.String(), "#282a36")
+	c.Contains(out.String(), "normal green / normal yellow")
+	c.Contains(out.String(), "ANSI color pairs fall under ΔE 10.0 with deuteranopia")
+
+	out.Reset()
+	confusions, err = CVD(out, dracula, cvdOptions{deficiency: palette.Tritanopia, threshold: 0})
+	c.NoError(err)
+	c.Empty(confusions)
+	c.Contains(out.String(), "No ANSI colors fall under ΔE 0.0 with tritanopia")
+
+	_, err = CVD(out, "NonExisting.toml", cvdOptions{deficiency: palette.Protanopia})
+	c.Error(err)
+}
+
+func TestRunCVDArgs(t *testing.T) {
+	c := require.New(t)
+
+	err := run([]string{"cvd"})
+	c.ErrorIs(err, ErrMissingTheme)
+
+	err = run([]string{"cvd", "Nord", "--type", "achromatopsia"})
+	c.ErrorIs(err, palette.ErrInvalidDeficiency)
+}
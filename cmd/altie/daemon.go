@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/schedule"
+	"github.com/copydataai/altie/internal/watch"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// settleDelay groups the changes an editor makes when saving a file.
+	settleDelay = 200 * time.Millisecond
+
+	// maxSleep bounds how long the daemon waits before checking the
+	// time, the timers don't advance while the machine is suspended.
+	maxSleep = time.Minute
+)
+
+// dirWatcher follows the themes directory, it's a watch.Watcher unless
+// watching isn't supported.
+type dirWatcher interface {
+	Add(path string) error
+	Remove(path string) error
+}
+
+// daemon applies the schedule of altie.conf as time passes.
+type daemon struct {
+	w           io.Writer
+	appConfig   *config.AppConfig
+	watcher     dirWatcher
+	altieConfig *config.ConfigThemes
+	schedule    *schedule.Schedule
+	next        time.Time
+}
+
+// load reads altie.conf and its schedule, and watches its themes
+// directory instead of the previous one.
+func (d *daemon) load() error {
+	altieConfig, err := config.CheckConfig(d.appConfig.ConfigFilePath)
+	if err != nil {
+		return err
+	}
+
+	s, err := schedule.Parse(altieConfig.Schedule)
+	if err != nil {
+		return err
+	}
+
+	err = d.watchThemes(altieConfig.Config.ThemesDirectory)
+	if err != nil {
+		return err
+	}
+
+	d.altieConfig, d.schedule = altieConfig, s
+
+	return nil
+}
+
+// watchThemes moves the watch to themesDirectory when it isn't the
+// watched one. The previous directory is only left once the new one is
+// watched.
+func (d *daemon) watchThemes(themesDirectory string) error {
+	previous := ""
+	if d.altieConfig != nil {
+		previous = d.altieConfig.Config.ThemesDirectory
+	}
+
+	if d.watcher == nil || themesDirectory == previous {
+		return nil
+	}
+
+	err := d.watcher.Add(themesDirectory)
+	if err != nil {
+		return err
+	}
+
+	if previous == "" {
+		return nil
+	}
+
+	err = d.watcher.Remove(previous)
+	if err != nil {
+		fmt.Fprintf(d.w, "Watching for changes failed: %v\n", err)
+	}
+
+	return nil
+}
+
+// apply applies the theme due now and waits for the next transition. A
+// failure is reported and tried again then.
+func (d *daemon) apply(force bool) {
+	now := time.Now()
+	_, d.next = d.schedule.At(now)
+
+	_, err := Auto(d.w, d.altieConfig, d.appConfig, now, force)
+	if err != nil {
+		fmt.Fprintf(d.w, "Applying the schedule failed: %v\n", err)
+	}
+}
+
+// reload reads altie.conf again, keeping the previous schedule when it's
+// invalid.
+func (d *daemon) reload() {
+	err := d.load()
+	if err != nil {
+		fmt.Fprintf(d.w, "Keeping the previous schedule: %v\n", err)
+		return
+	}
+
+	d.apply(false)
+}
+
+// changed reloads altie.conf when it's among paths, or applies the
+// current theme again when its file is.
+func (d *daemon) changed(paths map[string]bool) {
+	if paths[d.appConfig.ConfigFilePath] {
+		fmt.Fprintln(d.w, "altie.conf changed, reloading")
+		d.reload()
+
+		return
+	}
+
+	state, err := config.LoadState(d.appConfig.StatePath)
+	if err != nil {
+		fmt.Fprintf(d.w, "Reading the history failed: %v\n", err)
+		return
+	}
+
+	recent := state.Recent(1)
+	if len(recent) == 0 {
+		return
+	}
+
+	if paths[filepath.Join(d.altieConfig.Config.ThemesDirectory, filepath.FromSlash(recent[0]))] {
+		fmt.Fprintf(d.w, "%s changed, applying it again\n", recent[0])
+		d.apply(true)
+	}
+}
+
+// Daemon applies the schedule at each transition, reloads altie.conf on
+// SIGHUP or when it changes, and applies the current theme again when
+// its file changes, until it gets another signal. changes are the paths
+// of changed files, watchErrors what went wrong while watching them, and
+// watcher, when it isn't nil, is told which themes directory to watch.
+func Daemon(w io.Writer, appConfig *config.AppConfig, signals <-chan os.Signal, changes <-chan string, watchErrors <-chan error, watcher dirWatcher) error {
+	d := &daemon{w: w, appConfig: appConfig, watcher: watcher}
+
+	err := d.load()
+	if err != nil {
+		return err
+	}
+
+	d.apply(false)
+
+	changed := make(map[string]bool)
+	var settled <-chan time.Time
+
+	for {
+		wake := time.NewTimer(min(time.Until(d.next), maxSleep))
+
+		select {
+		case <-wake.C:
+			if !time.Now().Before(d.next) {
+				d.apply(false)
+			}
+		case sig := <-signals:
+			if sig != syscall.SIGHUP {
+				wake.Stop()
+				fmt.Fprintf(w, "Stopping on %s\n", sig)
+
+				return nil
+			}
+
+			fmt.Fprintln(w, "Reloading altie.conf")
+			d.reload()
+		case path := <-changes:
+			changed[path] = true
+			settled = time.After(settleDelay)
+		case <-settled:
+			d.changed(changed)
+			clear(changed)
+			settled = nil
+		case err := <-watchErrors:
+			fmt.Fprintf(w, "Watching for changes failed: %v\n", err)
+		}
+
+		wake.Stop()
+	}
+}
+
+func runDaemon(args []string) error {
+	flags := flag.NewFlagSet("daemon", flag.ContinueOnError)
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	_, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+
+	var changes <-chan string
+	var watchErrors <-chan error
+	var themesWatcher dirWatcher
+
+	watcher, err := watch.New()
+	switch {
+	case errors.Is(err, watch.ErrUnsupported):
+		pterm.Warning.Println("Changes aren't watched on this platform, send SIGHUP to reload altie.conf")
+	case err != nil:
+		return err
+	default:
+		defer watcher.Close()
+
+		// The daemon watches the themes directory altie.conf names
+		err = watcher.Add(appConfig.ConfigFilePath)
+		if err != nil {
+			return err
+		}
+
+		changes, watchErrors, themesWatcher = watcher.Events, watcher.Errors, watcher
+	}
+
+	return Daemon(os.Stdout, appConfig, signals, changes, watchErrors, themesWatcher)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/schedule"
+	"github.com/stretchr/testify/require"
+)
+
+// lightSchedule is a fixed schedule wanting the light theme now, or the
+// dark one when swapped.
+func lightSchedule(now time.Time, swapped bool) config.Schedule {
+	lightAt, darkAt := now.Add(-time.Hour).Format("15:04"), now.Add(2*time.Hour).Format("15:04")
+	if swapped {
+		lightAt, darkAt = darkAt, lightAt
+	}
+
+	return config.Schedule{
+		Mode:       schedule.ModeFixed,
+		LightTheme: "Solarized-Light.toml",
+		DarkTheme:  "Nord.toml",
+		LightAt:    lightAt,
+		DarkAt:     darkAt,
+	}
+}
+
+// fakeWatcher records the directories the daemon watches.
+type fakeWatcher struct {
+	watched []string
+}
+
+func (f *fakeWatcher) Add(path string) error {
+	f.watched = append(f.watched, path)
+	return nil
+}
+
+func (f *fakeWatcher) Remove(path string) error {
+	f.watched = slices.DeleteFunc(f.watched, func(watched string) bool {
+		return watched == path
+	})
+	return nil
+}
+
+func TestDaemon(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+	altieConfig.Schedule = lightSchedule(time.Now(), false)
+	c.NoError(altieConfig.Save(appConfig.ConfigFilePath))
+
+	reader, writer := io.Pipe()
+	lines := bufio.NewScanner(reader)
+	nextLine := func() string {
+		c.True(lines.Scan())
+		return lines.Text()
+	}
+
+	signals := make(chan os.Signal)
+	changes := make(chan string)
+	done := make(chan error)
+	watcher := &fakeWatcher{}
+
+	go func() {
+		done <- Daemon(writer, appConfig, signals, changes, nil, watcher)
+	}()
+
+	c.Contains(nextLine(), "Applied Solarized-Light.toml until")
+	c.Equal([]string{appConfig.ThemesDir}, watcher.watched)
+
+	altieConfig.Schedule = lightSchedule(time.Now(), true)
+	c.NoError(altieConfig.Save(appConfig.ConfigFilePath))
+
+	signals <- syscall.SIGHUP
+	c.Equal("Reloading altie.conf", nextLine())
+	c.Contains(nextLine(), "Applied Nord.toml until")
+
+	// An invalid altie.conf keeps the schedule running
+	c.NoError(os.WriteFile(appConfig.ConfigFilePath, []byte("[Schedule]\nMode = \"weekly\"\n"), 0o644))
+	changes <- appConfig.ConfigFilePath
+	c.Equal("altie.conf changed, reloading", nextLine())
+	c.Equal("Keeping the previous schedule: schedule needs a light and a dark theme", nextLine())
+
+	// Editing the applied theme applies it again, other themes are left
+	changes <- filepath.Join(appConfig.ThemesDir, "Solarized-Light.toml")
+	changes <- filepath.Join(appConfig.ThemesDir, "Nord.toml")
+	c.Equal("Nord.toml changed, applying it again", nextLine())
+	c.Contains(nextLine(), "Applied Nord.toml until")
+
+	// Moving the themes directory moves the watch along
+	movedDir := filepath.Join(tmpDir, "moved")
+	c.NoError(os.Rename(appConfig.ThemesDir, movedDir))
+	altieConfig.Config.ThemesDirectory = movedDir
+	c.NoError(altieConfig.Save(appConfig.ConfigFilePath))
+
+	signals <- syscall.SIGHUP
+	c.Equal("Reloading altie.conf", nextLine())
+	c.Contains(nextLine(), "Nord.toml is already applied until")
+	c.Equal([]string{movedDir}, watcher.watched)
+
+	changes <- filepath.Join(movedDir, "Nord.toml")
+	c.Equal("Nord.toml changed, applying it again", nextLine())
+	c.Contains(nextLine(), "Applied Nord.toml until")
+
+	signals <- syscall.SIGTERM
+	c.Equal("Stopping on terminated", nextLine())
+	c.NoError(<-done)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/pterm/pterm"
+)
+
+type dedupeOptions struct {
+	tolerance float64
+	hide      bool
+	delete    bool
+	reset     bool
+}
+
+// Dedupe reports the groups of duplicated themes, then hides or deletes
+// every theme of a group but its original when asked to. Deleted themes
+// are forgotten by the index and the state too.
+func Dedupe(w io.Writer, index *themes.Index, indexPath string, state *config.State, statePath string, options dedupeOptions) ([]themes.Duplicates, error) {
+	if options.reset {
+		index.Hidden = nil
+		fmt.Fprintln(w, "Every theme is shown again")
+
+		return nil, index.Save(indexPath)
+	}
+
+	groups := index.Duplicates(options.tolerance)
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "No duplicated themes found")
+		return groups, nil
+	}
+
+	data := [][]string{{"Original", "Duplicate", "ΔE"}}
+	duplicates := make([]string, 0)
+	for _, group := range groups {
+		for i, theme := range group.Themes {
+			original := ""
+			if i == 0 {
+				original = group.Original
+			}
+
+			data = append(data, []string{original, theme.Name, fmt.Sprintf("%.2f", theme.Distance)})
+			duplicates = append(duplicates, theme.Name)
+		}
+	}
+
+	err := pterm.DefaultTable.WithHasHeader().WithWriter(w).WithData(data).Render()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(w, "%d duplicates in %d groups\n", len(duplicates), len(groups))
+
+	switch {
+	case options.delete:
+		deleted := make([]string, 0, len(duplicates))
+		for _, name := range duplicates {
+			err = os.Remove(filepath.Join(index.ThemesDirectory, filepath.FromSlash(name)))
+			if err != nil {
+				break
+			}
+
+			deleted = append(deleted, name)
+		}
+
+		// What was deleted before a failure is forgotten all the same
+		index.Forget(deleted)
+		state.Forget(deleted...)
+
+		err = errors.Join(err, index.Save(indexPath), state.Save(statePath))
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(w, "Deleted %d duplicates\n", len(duplicates))
+	case options.hide:
+		index.Hide(duplicates)
+		err = index.Save(indexPath)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(w, "Hid %d duplicates from the picker, use --all to list them\n", len(duplicates))
+	}
+
+	return groups, nil
+}
+
+func runDedupe(args []string) error {
+	flags := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	options := dedupeOptions{}
+	flags.Float64Var(&options.tolerance, "tolerance", 0, "mean CIEDE2000 difference under which palettes are duplicates")
+	flags.BoolVar(&options.hide, "hide", false, "hide the duplicates from the picker")
+	flags.BoolVar(&options.delete, "delete", false, "delete the duplicated theme files")
+	flags.BoolVar(&options.reset, "reset", false, "show the hidden duplicates again")
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	index, err := loadIndex(altieConfig, appConfig)
+	if err != nil {
+		return err
+	}
+
+	state, err := config.LoadState(appConfig.StatePath)
+	if err != nil {
+		return err
+	}
+
+	_, err = Dedupe(os.Stdout, index, appConfig.IndexPath, state, appConfig.StatePath, options)
+	return err
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupe(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(themesDir, os.ModePerm))
+
+	copies := map[string]string{"Nord.toml": "Nord.toml", "Nord-Copy.toml": "Nord.toml", "Dracula.toml": "Dracula.toml"}
+	for name, source := range copies {
+		content, err := os.ReadFile(filepath.Join("..", "..", "themes", source))
+		c.NoError(err)
+		c.NoError(os.WriteFile(filepath.Join(themesDir, name), content, 0o644))
+	}
+
+	indexPath := filepath.Join(tmpDir, "index.json")
+	index, err := themes.LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+
+	statePath := filepath.Join(tmpDir, "state.json")
+	state := &config.State{}
+
+	out := &bytes.Buffer{}
+	groups, err := Dedupe(out, index, indexPath, state, statePath, dedupeOptions{})
+	c.NoError(err)
+	c.Len(groups, 1)
+	c.Contains(out.String(), "Nord-Copy.toml")
+	c.Contains(out.String(), "1 duplicates in 1 groups")
+	c.Empty(index.Hidden)
+
+	out.Reset()
+	_, err = Dedupe(out, index, indexPath, state, statePath, dedupeOptions{hide: true})
+	c.NoError(err)
+	c.Contains(out.String(), "Hid 1 duplicates")
+
+	index, err = themes.LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.Equal([]string{"Dracula.toml", "Nord-Copy.toml"}, index.Filter(themes.Filter{}))
+
+	out.Reset()
+	_, err = Dedupe(out, index, indexPath, state, statePath, dedupeOptions{reset: true})
+	c.NoError(err)
+
+	index, err = themes.LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.Empty(index.Hidden)
+
+	// Deleting a hidden duplicate forgets it everywhere
+	_, err = Dedupe(&bytes.Buffer{}, index, indexPath, state, statePath, dedupeOptions{hide: true})
+	c.NoError(err)
+
+	state.AddFavorite("Nord.toml")
+	state.AddFavorite("Dracula.toml")
+	state.AddTags("Nord.toml", "work")
+	state.AddHistory("Nord.toml", time.Now())
+	state.AddHistory("Dracula.toml", time.Now())
+
+	out.Reset()
+	_, err = Dedupe(out, index, indexPath, state, statePath, dedupeOptions{delete: true})
+	c.NoError(err)
+	c.Contains(out.String(), "Deleted 1 duplicates")
+	c.NoFileExists(filepath.Join(themesDir, "Nord.toml"))
+	c.FileExists(filepath.Join(themesDir, "Nord-Copy.toml"))
+
+	saved, err := themes.LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.Empty(saved.Hidden)
+
+	loaded, err := config.LoadState(statePath)
+	c.NoError(err)
+	c.Equal([]string{"Dracula.toml"}, loaded.Favorites)
+	c.Empty(loaded.Tags)
+	c.Equal([]string{"Dracula.toml"}, loaded.Recent(0))
+
+	// The index forgot the deleted theme, there's nothing left to delete
+	out.Reset()
+	groups, err = Dedupe(out, index, indexPath, state, statePath, dedupeOptions{delete: true})
+	c.NoError(err)
+	c.Empty(groups)
+
+	index, err = themes.LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+
+	out.Reset()
+	groups, err = Dedupe(out, index, indexPath, state, statePath, dedupeOptions{})
+	c.NoError(err)
+	c.Empty(groups)
+	c.Contains(out.String(), "No duplicated themes found")
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/copydataai/altie/internal/formats"
+	"github.com/copydataai/altie/internal/render"
+	"github.com/copydataai/altie/internal/themes"
+)
+
+// exportName is how an exported theme is called, its name without the
+// category nor the extension.
+func exportName(name string) string {
+	return strings.TrimSuffix(path.Base(name), path.Ext(name))
+}
+
+// Export writes the theme in themePath, named name, in format.
+func Export(w io.Writer, themePath string, name string, format formats.Format) error {
+	themePalette, err := themes.LoadPalette(themePath)
+	if err != nil {
+		return err
+	}
+
+	return formats.Export(w, format, exportName(name), themePalette)
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	to := flags.String("to", "", "format to export to, kitty, wezterm, foot, ghostty, xresources or windows-terminal")
+	output := flags.String("output", "", "file to write, the standard output by default")
+
+	names, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(names) != 1 {
+		return fmt.Errorf("%w: altie export [flags] <theme>", ErrMissingTheme)
+	}
+
+	format, err := formats.ParseExportFormat(*to)
+	if err != nil {
+		return err
+	}
+
+	altieConfig, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	themePath, err := themes.ResolveTheme(altieConfig.Config.ThemesDirectory, names[0])
+	if err != nil {
+		return err
+	}
+
+	name, err := themeName(altieConfig, names[0])
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return Export(os.Stdout, themePath, name, format)
+	}
+
+	return exportFile(*output, themePath, name, format)
+}
+
+// exportFile exports the theme into output, which is only replaced once
+// the export succeeded.
+func exportFile(output string, themePath string, name string, format formats.Format) error {
+	var content bytes.Buffer
+	err := Export(&content, themePath, name, format)
+	if err != nil {
+		return err
+	}
+
+	return render.Save([]render.Rendered{{Path: output, Content: content.Bytes()}})
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/copydataai/altie/internal/formats"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal("Mocha", exportName("catppuccin/Mocha.toml"))
+	c.Equal("Ashes.dark", exportName("Ashes.dark.toml"))
+
+	out := &bytes.Buffer{}
+	c.NoError(Export(out, filepath.Join("..", "..", "themes", "Nord.toml"), "Nord.toml", formats.Kitty))
+	c.True(strings.HasPrefix(out.String(), "# Nord, exported by altie\n"))
+	c.Contains(out.String(), "background #2e3440\n")
+
+	c.Error(Export(out, filepath.Join("..", "..", "themes", "Missing.toml"), "Missing.toml", formats.Kitty))
+}
+
+func TestExportFile(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	output := filepath.Join(tmpDir, "kitty", "Nord.conf")
+	c.NoError(exportFile(output, filepath.Join("..", "..", "themes", "Nord.toml"), "Nord.toml", formats.Kitty))
+
+	exported, err := os.ReadFile(output)
+	c.NoError(err)
+	c.True(strings.HasPrefix(string(exported), "# Nord, exported by altie\n"))
+
+	// A failed export leaves the file as it was
+	c.Error(exportFile(output, filepath.Join("..", "..", "themes", "Missing.toml"), "Missing.toml", formats.Kitty))
+
+	current, err := os.ReadFile(output)
+	c.NoError(err)
+	c.Equal(exported, current)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/copydataai/altie/internal/config"
+)
+
+var (
+	ErrUnknownFavAction = errors.New("unknown fav action, use add, rm or list")
+	ErrInvalidTag       = errors.New("tags can't be empty nor contain spaces")
+)
+
+// Fav adds the named themes to the favorites, removes them or lists the
+// favorites, saving the state in statePath when it changed.
+func Fav(w io.Writer, state *config.State, statePath string, action string, names []string) error {
+	switch action {
+	case "add":
+		for _, name := range names {
+			if !state.AddFavorite(name) {
+				fmt.Fprintf(w, "%s is already a favorite\n", name)
+			}
+		}
+	case "rm":
+		for _, name := range names {
+			if !state.RemoveFavorite(name) {
+				fmt.Fprintf(w, "%s isn't a favorite\n", name)
+			}
+		}
+	case "list":
+		for _, name := range state.Favorites {
+			fmt.Fprintln(w, name)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownFavAction, action)
+	}
+
+	return state.Save(statePath)
+}
+
+// parseTags checks the tags given on the command line, "#work" is the
+// same tag as "work".
+func parseTags(args []string) ([]string, error) {
+	tags := make([]string, 0, len(args))
+	for _, arg := range args {
+		tag := strings.TrimPrefix(arg, "#")
+		if tag == "" || strings.IndexFunc(tag, unicode.IsSpace) >= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTag, arg)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// Tag adds tags to the named theme, or removes them, and prints the tags
+// the theme ends up with.
+func Tag(w io.Writer, state *config.State, statePath string, name string, tags []string, remove bool) error {
+	if len(tags) > 0 {
+		if remove {
+			state.RemoveTags(name, tags...)
+		} else {
+			state.AddTags(name, tags...)
+		}
+
+		err := state.Save(statePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(state.Tags[name]) == 0 {
+		fmt.Fprintf(w, "%s has no tags\n", name)
+		return nil
+	}
+
+	fmt.Fprintf(w, "%s: #%s\n", name, strings.Join(state.Tags[name], " #"))
+
+	return nil
+}
+
+func runFav(args []string) error {
+	flags := flag.NewFlagSet("fav", flag.ContinueOnError)
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	action := "list"
+	if len(positional) > 0 {
+		action, positional = positional[0], positional[1:]
+	}
+
+	if action != "list" && len(positional) == 0 {
+		return fmt.Errorf("%w: altie fav add|rm <theme>...", ErrMissingTheme)
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(positional))
+	for _, arg := range positional {
+		name, err := themeName(altieConfig, arg)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, name)
+	}
+
+	state, err := config.LoadState(appConfig.StatePath)
+	if err != nil {
+		return err
+	}
+
+	return Fav(os.Stdout, state, appConfig.StatePath, action, names)
+}
+
+func runTag(args []string) error {
+	flags := flag.NewFlagSet("tag", flag.ContinueOnError)
+	remove := flags.Bool("rm", false, "remove the tags instead of adding them")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 0 {
+		return fmt.Errorf("%w: altie tag [--rm] <theme> [tag...]", ErrMissingTheme)
+	}
+
+	tags, err := parseTags(positional[1:])
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	name, err := themeName(altieConfig, positional[0])
+	if err != nil {
+		return err
+	}
+
+	state, err := config.LoadState(appConfig.StatePath)
+	if err != nil {
+		return err
+	}
+
+	return Tag(os.Stdout, state, appConfig.StatePath, name, tags, *remove)
+}
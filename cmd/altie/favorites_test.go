@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFav(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	statePath := filepath.Join(tmpDir, "state.json")
+	state := &config.State{}
+
+	out := &bytes.Buffer{}
+	c.NoError(Fav(out, state, statePath, "add", []string{"Nord.toml", "catppuccin/Mocha.toml"}))
+	c.NoError(Fav(out, state, statePath, "add", []string{"Nord.toml"}))
+	c.Equal("Nord.toml is already a favorite\n", out.String())
+
+	loaded, err := config.LoadState(statePath)
+	c.NoError(err)
+	c.Equal([]string{"Nord.toml", "catppuccin/Mocha.toml"}, loaded.Favorites)
+
+	out.Reset()
+	c.NoError(Fav(out, state, statePath, "rm", []string{"Nord.toml", "Dracula.toml"}))
+	c.Equal("Dracula.toml isn't a favorite\n", out.String())
+
+	out.Reset()
+	c.NoError(Fav(out, state, statePath, "list", nil))
+	c.Equal("catppuccin/Mocha.toml\n", out.String())
+
+	err = Fav(out, state, statePath, "remove", nil)
+	c.ErrorIs(err, ErrUnknownFavAction)
+}
+
+func TestTag(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	statePath := filepath.Join(tmpDir, "state.json")
+	state := &config.State{}
+
+	tags, err := parseTags([]string{"work", "#presentation"})
+	c.NoError(err)
+	c.Equal([]string{"work", "presentation"}, tags)
+
+	_, err = parseTags([]string{"#"})
+	c.ErrorIs(err, ErrInvalidTag)
+
+	_, err = parseTags([]string{"late night"})
+	c.ErrorIs(err, ErrInvalidTag)
+
+	out := &bytes.Buffer{}
+	c.NoError(Tag(out, state, statePath, "Nord.toml", tags, false))
+	c.Equal("Nord.toml: #presentation #work\n", out.String())
+
+	loaded, err := config.LoadState(statePath)
+	c.NoError(err)
+	c.Equal([]string{"Nord.toml"}, loaded.Tagged("work"))
+
+	out.Reset()
+	c.NoError(Tag(out, state, statePath, "Nord.toml", []string{"work"}, true))
+	c.Equal("Nord.toml: #presentation\n", out.String())
+
+	out.Reset()
+	c.NoError(Tag(out, state, statePath, "Dracula.toml", nil, false))
+	c.Equal("Dracula.toml has no tags\n", out.String())
+}
+
+func TestSelectThemes(t *testing.T) {
+	c := require.New(t)
+
+	state := &config.State{Favorites: []string{"Nord.toml", "Dracula.toml"}}
+	state.AddTags("Nord.toml", "work")
+	state.AddTags("Solarized.toml", "work")
+
+	c.Equal([]string{"Nord.toml", "Dracula.toml"}, selectThemes(state, true, ""))
+	c.Equal([]string{"Nord.toml", "Solarized.toml"}, selectThemes(state, false, "work"))
+	c.Equal([]string{"Nord.toml"}, selectThemes(state, true, "work"))
+	c.NotNil(selectThemes(state, false, "night"))
+	c.Empty(selectThemes(state, false, "night"))
+
+	// fav is the favorites
+	c.Equal([]string{"Nord.toml", "Dracula.toml"}, selectThemes(state, false, "fav"))
+}
+
+func TestThemeName(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	c.NoError(os.MkdirAll(filepath.Join(tmpDir, "catppuccin"), os.ModePerm))
+	c.NoError(os.WriteFile(filepath.Join(tmpDir, "catppuccin", "Mocha.toml"), nil, 0o644))
+
+	altieConfig := &config.ConfigThemes{Config: config.Config{ThemesDirectory: tmpDir}}
+
+	name, err := themeName(altieConfig, "Mocha")
+	c.NoError(err)
+	c.Equal("catppuccin/Mocha.toml", name)
+
+	_, err = themeName(altieConfig, "Latte")
+	c.Error(err)
+}
+
+func TestRunFavTagArgs(t *testing.T) {
+	c := require.New(t)
+
+	err := run([]string{"fav", "add"})
+	c.ErrorIs(err, ErrMissingTheme)
+
+	err = run([]string{"tag"})
+	c.ErrorIs(err, ErrMissingTheme)
+
+	err = run([]string{"tag", "Nord", "#"})
+	c.ErrorIs(err, ErrInvalidTag)
+}
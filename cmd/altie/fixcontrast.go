@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/pterm/pterm"
+)
+
+const accessibleSuffix = ".accessible"
+
+type fixContrastOptions struct {
+	target float64
+	space  palette.Space
+}
+
+// FixContrast writes next to themePath a copy of the theme whose failing
+// colors reach the target contrast, returning the name of the new theme.
+// Nothing is written when every color already reaches it, the name is
+// empty then. Fixing an accessible theme again replaces it.
+func FixContrast(w io.Writer, themePath string, options fixContrastOptions, creator themes.ThemeCreator) (string, error) {
+	themePalette, err := themes.LoadPalette(themePath)
+	if err != nil {
+		return "", err
+	}
+
+	_, adjustments := themePalette.FixContrast(options.target, options.space)
+	if len(adjustments) == 0 {
+		fmt.Fprintf(w, "Every color of %s already reaches %.1f:1, nothing to fix\n", filepath.Base(themePath), options.target)
+		return "", nil
+	}
+
+	content, err := themes.DeriveTheme(themePath, adjustments)
+	if err != nil {
+		return "", err
+	}
+
+	base := filepath.Base(themePath)
+	base = strings.TrimSuffix(strings.TrimSuffix(base, filepath.Ext(base)), accessibleSuffix)
+	name := base + accessibleSuffix + ".toml"
+	err = creator.CreateFile(name, content, filepath.Dir(themePath))
+	if err != nil {
+		return "", err
+	}
+
+	data := [][]string{{"Pair", "From", "To"}}
+	for _, adjustment := range adjustments {
+		data = append(data, []string{adjustment.Pair, adjustment.From.Hex(), adjustment.To.Hex()})
+	}
+
+	err = pterm.DefaultTable.WithHasHeader().WithWriter(w).WithData(data).Render()
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(w, "%d colors adjusted to reach %.1f:1, saved as %s\n", len(adjustments), options.target, name)
+
+	return name, nil
+}
+
+func runFixContrast(args []string) error {
+	flags := flag.NewFlagSet("fix-contrast", flag.ContinueOnError)
+	options := fixContrastOptions{}
+	flags.Float64Var(&options.target, "target", palette.WCAGAA, "WCAG contrast ratio the colors must reach")
+	space := flags.String("space", string(palette.SpaceOKLCH), "color space the lightness moves in, oklch or lab")
+
+	names, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(names) != 1 {
+		return fmt.Errorf("%w: altie fix-contrast [flags] <theme>", ErrMissingTheme)
+	}
+
+	options.space, err = palette.ParseSpace(*space)
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	themePath, err := themes.ResolveTheme(altieConfig.Config.ThemesDirectory, names[0])
+	if err != nil {
+		return err
+	}
+
+	name, err := FixContrast(os.Stdout, themePath, options, &themes.AltieTheme{})
+	if err != nil || name == "" {
+		return err
+	}
+
+	return markSource(altieConfig, appConfig, themes.SourceDerived, name)
+}
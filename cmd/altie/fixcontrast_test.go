@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+type mockThemeCreator struct {
+	createFile func(name string, content []byte, directory string) error
+}
+
+func (m *mockThemeCreator) CreateFile(name string, content []byte, directory string) error {
+	return m.createFile(name, content, directory)
+}
+
+func TestFixContrast(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	content, err := os.ReadFile(filepath.Join("..", "..", "themes", "Nord.toml"))
+	c.NoError(err)
+
+	nord := filepath.Join(tmpDir, "Nord.toml")
+	c.NoError(os.WriteFile(nord, content, 0o644))
+
+	out := &bytes.Buffer{}
+	options := fixContrastOptions{target: palette.WCAGAA, space: palette.SpaceOKLCH}
+	name, err := FixContrast(out, nord, options, &themes.AltieTheme{})
+	c.NoError(err)
+	c.Equal("Nord.accessible.toml", name)
+	c.Contains(out.String(), "bright black")
+	c.Contains(out.String(), "6 colors adjusted to reach 4.5:1, saved as Nord.accessible.toml")
+
+	accessible, err := themes.LoadPalette(filepath.Join(tmpDir, name))
+	c.NoError(err)
+	c.Equal("#909cb2", accessible.Bright[0].Hex())
+
+	// Fixing an accessible theme writes nothing
+	out.Reset()
+	written, err := FixContrast(out, filepath.Join(tmpDir, name), options, &themes.AltieTheme{})
+	c.NoError(err)
+	c.Empty(written)
+	c.Equal("Every color of Nord.accessible.toml already reaches 4.5:1, nothing to fix\n", out.String())
+
+	entries, err := os.ReadDir(tmpDir)
+	c.NoError(err)
+	c.Len(entries, 2)
+
+	// A higher target replaces it instead of chaining the suffix
+	out.Reset()
+	options.target = 7
+	written, err = FixContrast(out, filepath.Join(tmpDir, name), options, &themes.AltieTheme{})
+	c.NoError(err)
+	c.Equal("Nord.accessible.toml", written)
+	c.NoFileExists(filepath.Join(tmpDir, "Nord.accessible.accessible.toml"))
+	options.target = palette.WCAGAA
+
+	failing := &mockThemeCreator{func(name string, content []byte, directory string) error {
+		return errors.New("failed to create file")
+	}}
+	_, err = FixContrast(out, nord, options, failing)
+	c.EqualError(err, "failed to create file")
+
+	_, err = FixContrast(out, filepath.Join(tmpDir, "NonExisting.toml"), options, failing)
+	c.True(os.IsNotExist(err))
+}
+
+func TestRunFixContrastArgs(t *testing.T) {
+	c := require.New(t)
+
+	err := run([]string{"fix-contrast"})
+	c.ErrorIs(err, ErrMissingTheme)
+
+	err = run([]string{"fix-contrast", "Nord", "--space", "hsl"})
+	c.ErrorIs(err, palette.ErrInvalidSpace)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/copydataai/altie/internal/appearance"
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/schedule"
+)
+
+var ErrSettingsClosed = errors.New("lost the connection to the desktop settings")
+
+// followScheme applies the light or dark theme of the schedule matching
+// the preference of the desktop.
+func followScheme(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, scheme appearance.ColorScheme) error {
+	if altieConfig.Schedule.LightTheme == "" || altieConfig.Schedule.DarkTheme == "" {
+		return schedule.ErrMissingThemes
+	}
+
+	theme := altieConfig.Schedule.LightTheme
+	if scheme.Variant() == palette.Dark {
+		theme = altieConfig.Schedule.DarkTheme
+	}
+
+	name, applied, err := applyUnlessCurrent(w, altieConfig, appConfig, theme, false)
+	if err != nil {
+		return err
+	}
+
+	if !applied {
+		fmt.Fprintf(w, "The desktop prefers %s, %s is already applied\n", scheme, name)
+		return nil
+	}
+
+	fmt.Fprintf(w, "The desktop prefers %s, applied %s\n", scheme, name)
+
+	return nil
+}
+
+// FollowSystem applies the light or dark theme of the schedule matching
+// the preference of the desktop, then each time it changes until it gets
+// a signal.
+func FollowSystem(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, settings appearance.Settings, signals <-chan os.Signal) error {
+	// Watching first doesn't miss a change made while reading
+	schemes, err := settings.Watch()
+	if err != nil {
+		return err
+	}
+
+	scheme, err := settings.ColorScheme()
+	if err != nil {
+		return err
+	}
+
+	err = followScheme(w, altieConfig, appConfig, scheme)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case scheme, ok := <-schemes:
+			if !ok {
+				return ErrSettingsClosed
+			}
+
+			err = followScheme(w, altieConfig, appConfig, scheme)
+			if err != nil {
+				fmt.Fprintf(w, "Following the desktop failed: %v\n", err)
+			}
+		case sig := <-signals:
+			fmt.Fprintf(w, "Stopping on %s\n", sig)
+
+			return nil
+		}
+	}
+}
+
+func runFollowSystem(args []string) error {
+	flags := flag.NewFlagSet("follow-system", flag.ContinueOnError)
+	light := flags.String("light", "", "theme applied when the desktop prefers light, instead of the one of the schedule")
+	dark := flags.String("dark", "", "theme applied when the desktop prefers dark, instead of the one of the schedule")
+	once := flags.Bool("once", false, "apply the theme of the current preference and exit")
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if *light != "" {
+		altieConfig.Schedule.LightTheme = *light
+	}
+
+	if *dark != "" {
+		altieConfig.Schedule.DarkTheme = *dark
+	}
+
+	settings, err := appearance.ConnectPortal()
+	if err != nil {
+		return err
+	}
+
+	defer settings.Close()
+
+	if *once {
+		scheme, err := settings.ColorScheme()
+		if err != nil {
+			return err
+		}
+
+		return followScheme(os.Stdout, altieConfig, appConfig, scheme)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+
+	return FollowSystem(os.Stdout, altieConfig, appConfig, settings, signals)
+}
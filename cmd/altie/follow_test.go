@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/copydataai/altie/internal/appearance"
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/schedule"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSettings is a desktop whose preference changes through schemes.
+type fakeSettings struct {
+	scheme  appearance.ColorScheme
+	schemes chan appearance.ColorScheme
+}
+
+func (s *fakeSettings) ColorScheme() (appearance.ColorScheme, error) {
+	return s.scheme, nil
+}
+
+func (s *fakeSettings) Watch() (<-chan appearance.ColorScheme, error) {
+	return s.schemes, nil
+}
+
+func (s *fakeSettings) Close() error {
+	return nil
+}
+
+func TestFollowSystem(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+	settings := &fakeSettings{scheme: appearance.PreferDark, schemes: make(chan appearance.ColorScheme)}
+
+	c.ErrorIs(FollowSystem(io.Discard, altieConfig, appConfig, settings, nil), schedule.ErrMissingThemes)
+
+	altieConfig.Schedule = config.Schedule{LightTheme: "Solarized-Light", DarkTheme: "Nord"}
+
+	reader, writer := io.Pipe()
+	lines := bufio.NewScanner(reader)
+	nextLine := func() string {
+		c.True(lines.Scan())
+		return lines.Text()
+	}
+
+	signals := make(chan os.Signal)
+	done := make(chan error)
+
+	go func() {
+		done <- FollowSystem(writer, altieConfig, appConfig, settings, signals)
+	}()
+
+	c.Equal("The desktop prefers dark, applied Nord.toml", nextLine())
+
+	settings.schemes <- appearance.PreferLight
+	c.Equal("The desktop prefers light, applied Solarized-Light.toml", nextLine())
+
+	settings.schemes <- appearance.NoPreference
+	c.Equal("The desktop prefers no preference, Solarized-Light.toml is already applied", nextLine())
+
+	signals <- syscall.SIGTERM
+	c.Equal("Stopping on terminated", nextLine())
+	c.NoError(<-done)
+
+	close(settings.schemes)
+	c.ErrorIs(FollowSystem(io.Discard, altieConfig, appConfig, settings, nil), ErrSettingsClosed)
+}
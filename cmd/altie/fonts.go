@@ -0,0 +1,410 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/fonts"
+	"github.com/copydataai/altie/internal/picker"
+	"github.com/copydataai/altie/internal/themes"
+)
+
+var (
+	ErrUnknownFontsAction = errors.New("unknown fonts action, use list or select")
+	ErrNoFonts            = errors.New("no monospace font found")
+	ErrUnknownFontStyle   = errors.New("font style not installed")
+	ErrInvalidFontSize    = errors.New("the font size must be positive")
+)
+
+// ListFonts prints the font families of faces with their styles, the
+// monospace ones unless all is set, marking the current one.
+func ListFonts(w io.Writer, faces []fonts.Face, current string, all bool) {
+	families := fonts.Families(faces)
+	if !all {
+		families = fonts.Monospace(families)
+	}
+
+	for _, family := range families {
+		marker := "  "
+		if family.Name == current {
+			marker = "* "
+		}
+
+		fmt.Fprintf(w, "%s%s (%s)\n", marker, family.Name, strings.Join(family.Styles, ", "))
+	}
+}
+
+// fontStyle is the face a style of the font resolves to, named like
+// fonts.Family.MissingStyles names them.
+type fontStyle struct {
+	name string
+	face config.FontFace
+}
+
+// fontStyles resolves the faces of themeConfig, the ones without a family
+// take Font.
+func fontStyles(themeConfig config.ThemeConfig) []fontStyle {
+	faces := themeConfig.Faces
+	styles := []fontStyle{
+		{"normal", faces.Normal},
+		{"bold", faces.Bold},
+		{"italic", faces.Italic},
+		{"bold italic", faces.BoldItalic},
+	}
+
+	for i := range styles {
+		if styles[i].face.Family == "" {
+			styles[i].face.Family = themeConfig.Font
+		}
+	}
+
+	return styles
+}
+
+// checkedFamilies are the families of themeConfig that can be looked up,
+// fontconfig aliases like monospace always pass.
+func checkedFamilies(themeConfig config.ThemeConfig) []string {
+	families := make([]string, 0)
+	for _, style := range fontStyles(themeConfig) {
+		family := style.face.Family
+		if family != "" && !fonts.IsGeneric(family) && !slices.Contains(families, family) {
+			families = append(families, family)
+		}
+	}
+
+	return families
+}
+
+// checkFont makes sure the families of themeConfig are among the installed
+// faces, with the styles asked for, before alacritty is told to use them.
+// It warns about the styles Font lacks that no face replaces.
+func checkFont(w io.Writer, faces []fonts.Face, themeConfig config.ThemeConfig) error {
+	checked := checkedFamilies(themeConfig)
+	if len(checked) == 0 {
+		return nil
+	}
+
+	// Nothing to compare with, fonts may live where altie doesn't look
+	if len(faces) == 0 {
+		fmt.Fprintf(w, "Warning: no font found, can't check %s is installed\n", strings.Join(checked, ", "))
+		return nil
+	}
+
+	families := fonts.Families(faces)
+	for _, style := range fontStyles(themeConfig) {
+		if !slices.Contains(checked, style.face.Family) {
+			continue
+		}
+
+		family, err := fonts.Check(families, style.face.Family)
+		if err != nil {
+			return err
+		}
+
+		if style.face.Style != "" && !slices.ContainsFunc(family.Styles, func(s string) bool { return strings.EqualFold(s, style.face.Style) }) {
+			return fmt.Errorf("%w: %s has no %s style, only %s", ErrUnknownFontStyle, family.Name, style.face.Style, strings.Join(family.Styles, ", "))
+		}
+	}
+
+	if slices.Contains(checked, themeConfig.Font) {
+		family, _ := fonts.Find(families, themeConfig.Font)
+		warnMissingStyles(w, family, themeConfig.Faces)
+	}
+
+	return nil
+}
+
+// warnMissingStyles warns about the styles family has no face for, unless
+// faces give them another family or style.
+func warnMissingStyles(w io.Writer, family fonts.Family, faces config.FontFaces) {
+	replaced := map[string]bool{
+		"bold":        faces.Bold != config.FontFace{},
+		"italic":      faces.Italic != config.FontFace{},
+		"bold italic": faces.BoldItalic != config.FontFace{},
+	}
+
+	missing := make([]string, 0)
+	for _, style := range family.MissingStyles() {
+		if !replaced[style] {
+			missing = append(missing, style)
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Fprintf(w, "Warning: %s has no %s face, alacritty will draw them from the regular one\n", family.Name, strings.Join(missing, ", "))
+	}
+}
+
+// installedFaces reads the installed fonts, only when themeConfig has a
+// family to check. They're cached until a font directory changes.
+func installedFaces(appConfig *config.AppConfig, themeConfig config.ThemeConfig) ([]fonts.Face, error) {
+	if len(checkedFamilies(themeConfig)) == 0 {
+		return nil, nil
+	}
+
+	return fonts.Load(appConfig.FontCachePath, appConfig.FontDirs)
+}
+
+// checkConfiguredFont looks for the fonts of altie.conf among the
+// installed ones, read from the cache unless a font directory changed.
+func checkConfiguredFont(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig) error {
+	faces, err := installedFaces(appConfig, altieConfig.ThemeConfig)
+	if err != nil {
+		return err
+	}
+
+	return checkFont(w, faces, altieConfig.ThemeConfig)
+}
+
+// SetFont makes family the font of altie.conf and of the alacritty config.
+func SetFont(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, family fonts.Family) error {
+	warnMissingStyles(w, family, altieConfig.Faces)
+
+	altieConfig.Font = family.Name
+
+	err := saveFont(altieConfig, appConfig)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Font set to %s\n", family.Name)
+
+	return nil
+}
+
+// SetFontConfig replaces the font settings of altie.conf with themeConfig,
+// once its fonts are found in faces, and writes them to the alacritty
+// config.
+func SetFontConfig(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, faces []fonts.Face, themeConfig config.ThemeConfig) error {
+	err := checkFont(w, faces, themeConfig)
+	if err != nil {
+		return err
+	}
+
+	altieConfig.ThemeConfig = themeConfig
+
+	err = saveFont(altieConfig, appConfig)
+	if err != nil {
+		return err
+	}
+
+	ShowFont(w, altieConfig.ThemeConfig)
+
+	return nil
+}
+
+func saveFont(altieConfig *config.ConfigThemes, appConfig *config.AppConfig) error {
+	err := altieConfig.Save(appConfig.ConfigFilePath)
+	if err != nil {
+		return err
+	}
+
+	return themes.ApplyFontTheme(appConfig.AlacrittyConfig, &altieConfig.ThemeConfig)
+}
+
+// ShowFont prints the font settings of altie.conf.
+func ShowFont(w io.Writer, themeConfig config.ThemeConfig) {
+	fmt.Fprintf(w, "Font: %s, size %d\n", themeConfig.Font, themeConfig.FontSize)
+
+	for _, style := range fontStyles(themeConfig) {
+		face := style.face.Family
+		if style.face.Style != "" {
+			face += ", style " + style.face.Style
+		}
+
+		fmt.Fprintf(w, "%s%s: %s\n", strings.ToUpper(style.name[:1]), style.name[1:], face)
+	}
+
+	fmt.Fprintf(w, "Offset: %d, %d\n", themeConfig.Offset.X, themeConfig.Offset.Y)
+	fmt.Fprintf(w, "Glyph offset: %d, %d\n", themeConfig.GlyphOffset.X, themeConfig.GlyphOffset.Y)
+
+	builtinBoxDrawing := "alacritty's default"
+	if themeConfig.BuiltinBoxDrawing != nil {
+		builtinBoxDrawing = strconv.FormatBool(*themeConfig.BuiltinBoxDrawing)
+	}
+
+	fmt.Fprintf(w, "Builtin box drawing: %s\n", builtinBoxDrawing)
+}
+
+// fontItems lists the monospace families for the picker.
+func fontItems(faces []fonts.Face) []picker.Item {
+	families := fonts.Monospace(fonts.Families(faces))
+	items := make([]picker.Item, 0, len(families))
+	for _, family := range families {
+		items = append(items, picker.Item{Name: family.Name, Section: picker.SectionThemes})
+	}
+
+	return items
+}
+
+func runFonts(args []string) error {
+	flags := flag.NewFlagSet("fonts", flag.ContinueOnError)
+	all := flags.Bool("all", false, "also list the fonts that aren't monospace")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	action := "list"
+	if len(positional) > 0 {
+		action = positional[0]
+	}
+
+	if action != "list" && action != "select" {
+		return fmt.Errorf("%w: %s", ErrUnknownFontsAction, action)
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	faces, err := fonts.Load(appConfig.FontCachePath, appConfig.FontDirs)
+	if err != nil {
+		return err
+	}
+
+	if action == "list" {
+		ListFonts(os.Stdout, faces, altieConfig.Font, *all)
+		return nil
+	}
+
+	items := fontItems(faces)
+	if len(items) == 0 {
+		return ErrNoFonts
+	}
+
+	selected, err := picker.New("Select a font", items).Run()
+	if err != nil {
+		return err
+	}
+
+	family, _ := fonts.Find(fonts.Families(faces), selected.Name)
+
+	return SetFont(os.Stdout, altieConfig, appConfig, family)
+}
+
+// fontFlags are the settings given to altie font, set tells which flags
+// were used.
+type fontFlags struct {
+	family, style                     string
+	boldFamily, boldStyle             string
+	italicFamily, italicStyle         string
+	boldItalicFamily, boldItalicStyle string
+	size                              int64
+	offsetX, offsetY                  int64
+	glyphOffsetX, glyphOffsetY        int64
+	builtinBoxDrawing                 bool
+	set                               map[string]bool
+}
+
+// update changes the font settings with the flags that were set, an empty
+// family gives the style back to the font. A size is only checked when
+// it's given, 0 in altie.conf leaves it to alacritty.
+func (f fontFlags) update(cfg config.ThemeConfig) (config.ThemeConfig, error) {
+	if f.set["size"] && f.size <= 0 {
+		return cfg, fmt.Errorf("%w: %d", ErrInvalidFontSize, f.size)
+	}
+
+	texts := []struct {
+		name   string
+		value  string
+		target *string
+	}{
+		{"family", f.family, &cfg.Font},
+		{"style", f.style, &cfg.Faces.Normal.Style},
+		{"bold-family", f.boldFamily, &cfg.Faces.Bold.Family},
+		{"bold-style", f.boldStyle, &cfg.Faces.Bold.Style},
+		{"italic-family", f.italicFamily, &cfg.Faces.Italic.Family},
+		{"italic-style", f.italicStyle, &cfg.Faces.Italic.Style},
+		{"bold-italic-family", f.boldItalicFamily, &cfg.Faces.BoldItalic.Family},
+		{"bold-italic-style", f.boldItalicStyle, &cfg.Faces.BoldItalic.Style},
+	}
+	for _, text := range texts {
+		if f.set[text.name] {
+			*text.target = text.value
+		}
+	}
+
+	numbers := []struct {
+		name   string
+		value  int64
+		target *int64
+	}{
+		{"size", f.size, &cfg.FontSize},
+		{"offset-x", f.offsetX, &cfg.Offset.X},
+		{"offset-y", f.offsetY, &cfg.Offset.Y},
+		{"glyph-offset-x", f.glyphOffsetX, &cfg.GlyphOffset.X},
+		{"glyph-offset-y", f.glyphOffsetY, &cfg.GlyphOffset.Y},
+	}
+	for _, number := range numbers {
+		if f.set[number.name] {
+			*number.target = number.value
+		}
+	}
+
+	if f.set["builtin-box-drawing"] {
+		builtinBoxDrawing := f.builtinBoxDrawing
+		cfg.BuiltinBoxDrawing = &builtinBoxDrawing
+	}
+
+	return cfg, nil
+}
+
+func runFont(args []string) error {
+	flags := flag.NewFlagSet("font", flag.ContinueOnError)
+	options := fontFlags{set: make(map[string]bool)}
+	flags.StringVar(&options.family, "family", "", "font family of every style without its own")
+	flags.StringVar(&options.style, "style", "", "style of the normal text, like Medium")
+	flags.StringVar(&options.boldFamily, "bold-family", "", "font family of bold text, empty for the font family")
+	flags.StringVar(&options.boldStyle, "bold-style", "", "style of bold text")
+	flags.StringVar(&options.italicFamily, "italic-family", "", "font family of italic text, empty for the font family")
+	flags.StringVar(&options.italicStyle, "italic-style", "", "style of italic text")
+	flags.StringVar(&options.boldItalicFamily, "bold-italic-family", "", "font family of bold italic text, empty for the font family")
+	flags.StringVar(&options.boldItalicStyle, "bold-italic-style", "", "style of bold italic text")
+	flags.Int64Var(&options.size, "size", 0, "font size in points")
+	flags.Int64Var(&options.offsetX, "offset-x", 0, "pixels added between characters")
+	flags.Int64Var(&options.offsetY, "offset-y", 0, "pixels added between lines")
+	flags.Int64Var(&options.glyphOffsetX, "glyph-offset-x", 0, "pixels the glyphs move right in their cell")
+	flags.Int64Var(&options.glyphOffsetY, "glyph-offset-y", 0, "pixels the glyphs move up in their cell")
+	flags.BoolVar(&options.builtinBoxDrawing, "builtin-box-drawing", true, "let alacritty draw the box drawing characters itself")
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		options.set[f.Name] = true
+	})
+
+	if len(options.set) == 0 {
+		ShowFont(os.Stdout, altieConfig.ThemeConfig)
+		return nil
+	}
+
+	themeConfig, err := options.update(altieConfig.ThemeConfig)
+	if err != nil {
+		return err
+	}
+
+	faces, err := installedFaces(appConfig, themeConfig)
+	if err != nil {
+		return err
+	}
+
+	return SetFontConfig(os.Stdout, altieConfig, appConfig, faces, themeConfig)
+}
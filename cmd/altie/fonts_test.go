@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/fonts"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+var testFaces = []fonts.Face{
+	{Family: "Fira Code", Style: "Regular", Monospace: true},
+	{Family: "Fira Code", Style: "Bold", Monospace: true, Bold: true},
+	{Family: "DejaVu Sans", Style: "Book"},
+	{Family: "DejaVu Sans Mono", Style: "Book", Monospace: true},
+}
+
+func TestListFonts(t *testing.T) {
+	c := require.New(t)
+
+	out := &bytes.Buffer{}
+	ListFonts(out, testFaces, "Fira Code", false)
+	c.Equal("  DejaVu Sans Mono (Book)\n* Fira Code (Bold, Regular)\n", out.String())
+
+	out.Reset()
+	ListFonts(out, testFaces, "", true)
+	c.Equal("  DejaVu Sans (Book)\n  DejaVu Sans Mono (Book)\n  Fira Code (Bold, Regular)\n", out.String())
+
+	items := fontItems(testFaces)
+	c.Len(items, 2)
+	c.Equal("DejaVu Sans Mono", items[0].Name)
+}
+
+func TestSetFont(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+
+	family, ok := fonts.Find(fonts.Families(testFaces), "Fira Code")
+	c.True(ok)
+
+	out := &bytes.Buffer{}
+	c.NoError(SetFont(out, altieConfig, appConfig, family))
+	c.Equal("Warning: Fira Code has no italic, bold italic face, alacritty will draw them from the regular one\nFont set to Fira Code\n", out.String())
+
+	saved, err := config.CheckConfig(appConfig.ConfigFilePath)
+	c.NoError(err)
+	c.Equal("Fira Code", saved.Font)
+
+	alacritty, err := themes.CheckAlacrittyConfig(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Equal(map[string]any{"family": "Fira Code"}, alacritty["font"].(map[string]any)["bold"])
+}
+
+func TestCheckFont(t *testing.T) {
+	c := require.New(t)
+
+	out := &bytes.Buffer{}
+	c.NoError(checkFont(out, testFaces, config.ThemeConfig{Font: "DejaVu Sans Mono"}))
+	c.Equal("Warning: DejaVu Sans Mono has no bold, italic, bold italic face, alacritty will draw them from the regular one\n", out.String())
+
+	// Only the styles no face replaces are missing
+	out.Reset()
+	c.NoError(checkFont(out, testFaces, config.ThemeConfig{
+		Font:  "DejaVu Sans Mono",
+		Faces: config.FontFaces{Bold: config.FontFace{Family: "Fira Code", Style: "bold"}},
+	}))
+	c.Equal("Warning: DejaVu Sans Mono has no italic, bold italic face, alacritty will draw them from the regular one\n", out.String())
+
+	out.Reset()
+	c.NoError(checkFont(out, nil, config.ThemeConfig{Font: "monospace"}))
+	c.NoError(checkFont(out, nil, config.ThemeConfig{}))
+	c.Empty(out.String())
+
+	err := checkFont(out, testFaces, config.ThemeConfig{Font: "Fira Cdoe"})
+	c.ErrorIs(err, fonts.ErrUnknownFamily)
+	c.EqualError(err, "font family not installed: Fira Cdoe, did you mean Fira Code?")
+
+	err = checkFont(out, testFaces, config.ThemeConfig{
+		Font:  "monospace",
+		Faces: config.FontFaces{Italic: config.FontFace{Family: "Fira Code Italic"}},
+	})
+	c.ErrorIs(err, fonts.ErrUnknownFamily)
+
+	err = checkFont(out, testFaces, config.ThemeConfig{
+		Font:  "Fira Code",
+		Faces: config.FontFaces{Normal: config.FontFace{Style: "Retina"}},
+	})
+	c.ErrorIs(err, ErrUnknownFontStyle)
+	c.EqualError(err, "font style not installed: Fira Code has no Retina style, only Bold, Regular")
+
+	c.NoError(checkFont(out, nil, config.ThemeConfig{Font: "Fira Code", Faces: config.FontFaces{Bold: config.FontFace{Family: "Iosevka"}}}))
+	c.Equal("Warning: no font found, can't check Fira Code, Iosevka is installed\n", out.String())
+}
+
+// cacheFaces makes faces the installed fonts of appConfig, through a
+// cache of a font directory that doesn't exist.
+func cacheFaces(c *require.Assertions, appConfig *config.AppConfig, faces []fonts.Face) {
+	appConfig.FontDirs = []string{filepath.Join(appConfig.HomeDir, "fonts")}
+
+	content, err := json.Marshal(map[string]any{"version": 1, "roots": appConfig.FontDirs, "faces": faces})
+	c.NoError(err)
+	c.NoError(os.WriteFile(appConfig.FontCachePath, content, 0o644))
+}
+
+func TestCheckConfiguredFont(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+	cacheFaces(c, appConfig, testFaces)
+
+	// A family that isn't installed leaves alacritty.toml alone
+	altieConfig.Font = "Fira Cdoe"
+	out := &bytes.Buffer{}
+	err = Apply(out, altieConfig, appConfig, "Nord", false)
+	c.ErrorIs(err, fonts.ErrUnknownFamily)
+	c.EqualError(err, "font family not installed: Fira Cdoe, did you mean Fira Code?")
+
+	content, err := os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Empty(content)
+
+	// Missing styles only warn
+	altieConfig.Font = "Fira Code"
+	c.NoError(Apply(out, altieConfig, appConfig, "Nord", false))
+	c.Contains(out.String(), "Warning: Fira Code has no italic, bold italic face")
+
+	alacritty, err := themes.CheckAlacrittyConfig(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Equal(map[string]any{"family": "Fira Code"}, alacritty["font"].(map[string]any)["normal"])
+}
+
+func TestFontFlags(t *testing.T) {
+	c := require.New(t)
+
+	cfg := config.ThemeConfig{
+		Font:     "Fira Code",
+		FontSize: 12,
+		Faces:    config.FontFaces{Italic: config.FontFace{Family: "Victor Mono"}},
+	}
+
+	options := fontFlags{
+		boldFamily: "DejaVu Sans Mono",
+		offsetY:    1,
+		set:        map[string]bool{"bold-family": true, "offset-y": true, "italic-family": true, "builtin-box-drawing": true},
+	}
+
+	updated, err := options.update(cfg)
+	c.NoError(err)
+	c.Equal("Fira Code", updated.Font)
+	c.Equal(int64(12), updated.FontSize)
+	c.Equal(config.FontFace{Family: "DejaVu Sans Mono"}, updated.Faces.Bold)
+	// An empty family gives the style back to the font
+	c.Equal(config.FontFace{}, updated.Faces.Italic)
+	c.Equal(config.FontOffset{Y: 1}, updated.Offset)
+	c.False(*updated.BuiltinBoxDrawing)
+
+	c.Equal("Victor Mono", cfg.Faces.Italic.Family)
+	c.Nil(cfg.BuiltinBoxDrawing)
+
+	// The size is only checked when it's given
+	options = fontFlags{offsetY: 2, set: map[string]bool{"offset-y": true}}
+	updated, err = options.update(config.ThemeConfig{Font: "Fira Code"})
+	c.NoError(err)
+	c.Equal(int64(0), updated.FontSize)
+
+	options = fontFlags{size: 0, set: map[string]bool{"size": true}}
+	_, err = options.update(cfg)
+	c.ErrorIs(err, ErrInvalidFontSize)
+}
+
+func TestSetFontConfig(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	altieConfig, appConfig := setupSchedule(c, tmpDir)
+
+	themeConfig := altieConfig.ThemeConfig
+	themeConfig.Font = "Fira Code"
+	themeConfig.Faces.BoldItalic = config.FontFace{Family: "DejaVu Sans Mono", Style: "Book"}
+	themeConfig.GlyphOffset = config.FontOffset{X: 1, Y: -1}
+
+	out := &bytes.Buffer{}
+	c.NoError(SetFontConfig(out, altieConfig, appConfig, testFaces, themeConfig))
+	c.Equal(`Warning: Fira Code has no italic face, alacritty will draw them from the regular one
+Font: Fira Code, size 14
+Normal: Fira Code
+Bold: Fira Code
+Italic: Fira Code
+Bold italic: DejaVu Sans Mono, style Book
+Offset: 0, 0
+Glyph offset: 1, -1
+Builtin box drawing: alacritty's default
+`, out.String())
+
+	saved, err := config.CheckConfig(appConfig.ConfigFilePath)
+	c.NoError(err)
+	c.Equal(themeConfig.Faces, saved.Faces)
+	c.Equal(themeConfig.GlyphOffset, saved.GlyphOffset)
+
+	alacritty, err := themes.CheckAlacrittyConfig(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	font := alacritty["font"].(map[string]any)
+	c.Equal(map[string]any{"family": "DejaVu Sans Mono", "style": "Book"}, font["bold_italic"])
+	c.Equal(map[string]any{"x": int64(1), "y": int64(-1)}, font["glyph_offset"])
+
+	// Nothing is saved when a font is missing
+	themeConfig.Faces.Bold.Family = "Comic Mono"
+	c.ErrorIs(SetFontConfig(out, altieConfig, appConfig, testFaces, themeConfig), fonts.ErrUnknownFamily)
+
+	saved, err = config.CheckConfig(appConfig.ConfigFilePath)
+	c.NoError(err)
+	c.Empty(saved.Faces.Bold.Family)
+
+	// Without a size alacritty keeps its own
+	themeConfig.Faces.Bold.Family = ""
+	themeConfig.FontSize = 0
+	c.NoError(SetFontConfig(out, altieConfig, appConfig, testFaces, themeConfig))
+
+	alacritty, err = themes.CheckAlacrittyConfig(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.NotContains(alacritty["font"].(map[string]any), "size")
+}
+
+func TestRunFontsArgs(t *testing.T) {
+	require.ErrorIs(t, runFonts([]string{"install"}), ErrUnknownFontsAction)
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/copydataai/altie/internal/formats"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+)
+
+var (
+	ErrMissingImport = errors.New("missing file to import")
+	ErrThemeExists   = errors.New("theme already exists, overwrite it with --force")
+	ErrImportName    = errors.New("--name only names a single theme or directory")
+)
+
+type importOptions struct {
+	// format of the file, guessed from its extension when empty
+	format formats.Format
+	// name of the theme, the name of the file when empty
+	name  string
+	force bool
+}
+
+// importName is the name of the theme imported from file, "name.toml"
+// unless it's given.
+func importName(file, name string) string {
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	if !strings.HasSuffix(name, ".toml") {
+		name += ".toml"
+	}
+
+	return name
+}
+
+// schemeName is the name of a theme imported from a file holding several,
+// in the category when it's set.
+func schemeName(scheme, category string) string {
+	return path.Join(category, strings.ReplaceAll(scheme, "/", "-")+".toml")
+}
+
+// saveImported writes the palette read from source as the theme name.
+func saveImported(w io.Writer, source string, name string, themePalette *palette.Palette, themesDirectory string, options importOptions, creator themes.ThemeCreator) error {
+	directory := filepath.Join(themesDirectory, filepath.FromSlash(path.Dir(name)))
+
+	_, err := os.Stat(filepath.Join(directory, path.Base(name)))
+	if err == nil && !options.force {
+		return fmt.Errorf("%w: %s", ErrThemeExists, name)
+	}
+
+	encoded, err := themes.EncodePalette(themePalette)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(directory, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	err = creator.CreateFile(path.Base(name), encoded, directory)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Imported %s as %s, a %s theme\n", source, name, themePalette.Variant())
+
+	return nil
+}
+
+// Import converts the theme in file to an Alacritty theme in the themes
+// directory and returns its name. A file holding several themes, like the
+// Windows Terminal settings, gives one theme per scheme named after it, in
+// the category options.name when it's set.
+func Import(w io.Writer, file string, themesDirectory string, options importOptions, creator themes.ThemeCreator) ([]string, error) {
+	format := options.format
+	if format == "" {
+		var err error
+		format, err = formats.DetectFormat(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	schemes, err := formats.ImportSchemes(format, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+	}
+
+	if len(schemes) == 1 {
+		name := importName(file, options.name)
+
+		err = saveImported(w, filepath.Base(file), name, schemes[0].Palette, themesDirectory, options, creator)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{name}, nil
+	}
+
+	names := make([]string, 0, len(schemes))
+	for _, scheme := range schemes {
+		name := schemeName(scheme.Name, options.name)
+
+		err = saveImported(w, scheme.Name+" of "+filepath.Base(file), name, scheme.Palette, themesDirectory, options, creator)
+		if err != nil {
+			return names, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// ImportDir imports the files of directory in options.format, or in a
+// format known from their extension, into the category options.name when
+// it's set, and returns the names of the themes. Files that fail to import
+// are reported and skipped.
+func ImportDir(w io.Writer, directory string, themesDirectory string, options importOptions, creator themes.ThemeCreator) ([]string, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	skipped := 0
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		file := filepath.Join(directory, entry.Name())
+		if options.format == "" {
+			if _, err := formats.DetectFormat(file); err != nil {
+				continue
+			}
+		}
+
+		fileOptions := options
+		fileOptions.name = ""
+		if options.name != "" {
+			fileOptions.name = path.Join(options.name, importName(file, ""))
+		}
+
+		imported, err := Import(w, file, themesDirectory, fileOptions, creator)
+		names = append(names, imported...)
+		if err != nil {
+			fmt.Fprintf(w, "Skipped %s: %v\n", entry.Name(), err)
+			skipped++
+		}
+	}
+
+	fmt.Fprintf(w, "Imported %d themes from %s, skipped %d\n", len(names), directory, skipped)
+
+	return names, nil
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := flags.String("from", "", "format of the files, guessed from their extension by default")
+	flags.StringVar(from, "format", "", "same as --from")
+	options := importOptions{}
+	flags.StringVar(&options.name, "name", "", "name of the theme, the name of the file by default, or category of the themes of a directory")
+	flags.BoolVar(&options.force, "force", false, "overwrite a theme with the same name")
+
+	files, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(files) == 0:
+		return fmt.Errorf("%w: altie import [flags] <file>...", ErrMissingImport)
+	case len(files) > 1 && options.name != "":
+		return ErrImportName
+	}
+
+	if *from != "" {
+		options.format, err = formats.ParseImportFormat(*from)
+		if err != nil {
+			return err
+		}
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			imported, err := ImportDir(os.Stdout, file, altieConfig.Config.ThemesDirectory, options, &themes.AltieTheme{})
+			if err != nil {
+				return err
+			}
+
+			names = append(names, imported...)
+
+			continue
+		}
+
+		imported, err := Import(os.Stdout, file, altieConfig.Config.ThemesDirectory, options, &themes.AltieTheme{})
+		if err != nil {
+			return err
+		}
+
+		names = append(names, imported...)
+	}
+
+	return markSource(altieConfig, appConfig, themes.SourceImported, names...)
+}
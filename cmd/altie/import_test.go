@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/copydataai/altie/internal/formats"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	c.Equal("Nord.toml", importName("/tmp/Nord.itermcolors", ""))
+	c.Equal("nord/Polar.toml", importName("/tmp/Nord.itermcolors", "nord/Polar"))
+
+	file := filepath.Join("..", "..", "internal", "formats", "testdata", "Nord.itermcolors")
+
+	out := &bytes.Buffer{}
+	names, err := Import(out, file, tmpDir, importOptions{}, &themes.AltieTheme{})
+	c.NoError(err)
+	c.Equal([]string{"Nord.toml"}, names)
+	c.Equal("Imported Nord.itermcolors as Nord.toml, a dark theme\n", out.String())
+
+	imported, err := themes.LoadPalette(filepath.Join(tmpDir, "Nord.toml"))
+	c.NoError(err)
+
+	nord, err := themes.LoadPalette(filepath.Join("..", "..", "themes", "Nord.toml"))
+	c.NoError(err)
+	c.Equal(nord, imported)
+
+	_, err = Import(out, file, tmpDir, importOptions{}, &themes.AltieTheme{})
+	c.ErrorIs(err, ErrThemeExists)
+
+	_, err = Import(out, file, tmpDir, importOptions{force: true}, &themes.AltieTheme{})
+	c.NoError(err)
+
+	// A name with a category creates its directory
+	names, err = Import(out, file, tmpDir, importOptions{name: "nord/Polar"}, &themes.AltieTheme{})
+	c.NoError(err)
+	c.Equal([]string{"nord/Polar.toml"}, names)
+	c.FileExists(filepath.Join(tmpDir, "nord", "Polar.toml"))
+
+	renamed := filepath.Join(tmpDir, "Nord.plist")
+	content, err := os.ReadFile(file)
+	c.NoError(err)
+	c.NoError(os.WriteFile(renamed, content, 0o644))
+
+	_, err = Import(out, renamed, tmpDir, importOptions{}, &themes.AltieTheme{})
+	c.ErrorIs(err, formats.ErrUnknownFormat)
+
+	_, err = Import(out, renamed, tmpDir, importOptions{format: formats.ITerm2, name: "Plist"}, &themes.AltieTheme{})
+	c.NoError(err)
+
+	failing := &mockThemeCreator{func(name string, content []byte, directory string) error {
+		return errors.New("failed to create file")
+	}}
+	_, err = Import(out, file, tmpDir, importOptions{name: "Other"}, failing)
+	c.EqualError(err, "failed to create file")
+
+	// Every scheme of the Windows Terminal settings is imported
+	scheme, err := os.ReadFile(filepath.Join("..", "..", "internal", "formats", "testdata", "Nord.windows-terminal.golden"))
+	c.NoError(err)
+
+	settings := filepath.Join(tmpDir, "settings.json")
+	copied := strings.Replace(string(scheme), `"name": "Nord"`, `"name": "Nord/Frost"`, 1)
+	c.NoError(os.WriteFile(settings, []byte(`{"schemes": [`+string(scheme)+`, `+copied+`]}`), 0o644))
+
+	out.Reset()
+	names, err = Import(out, settings, tmpDir, importOptions{name: "wt"}, &themes.AltieTheme{})
+	c.NoError(err)
+	c.Equal([]string{"wt/Nord.toml", "wt/Nord-Frost.toml"}, names)
+	c.Contains(out.String(), "Imported Nord/Frost of settings.json as wt/Nord-Frost.toml, a dark theme\n")
+}
+
+func TestRunImportArgs(t *testing.T) {
+	c := require.New(t)
+
+	c.ErrorIs(runImport(nil), ErrMissingImport)
+	c.ErrorIs(runImport([]string{"a.itermcolors", "b.itermcolors", "--name", "Nord"}), ErrImportName)
+	c.ErrorIs(runImport([]string{"a.itermcolors", "--from", "vscode"}), formats.ErrUnknownFormat)
+}
+
+func TestImportDir(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	schemes := filepath.Join(tmpDir, "schemes")
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(schemes, os.ModePerm))
+	c.NoError(os.MkdirAll(themesDir, os.ModePerm))
+
+	testdata := filepath.Join("..", "..", "internal", "formats", "testdata")
+	for _, name := range []string{"nord.base16.yaml", "dracula.base24.yaml"} {
+		content, err := os.ReadFile(filepath.Join(testdata, name))
+		c.NoError(err)
+		c.NoError(os.WriteFile(filepath.Join(schemes, name), content, 0o644))
+	}
+
+	c.NoError(os.WriteFile(filepath.Join(schemes, "broken.yaml"), []byte("base00: \"2E3440\"\n"), 0o644))
+	c.NoError(os.WriteFile(filepath.Join(schemes, "README.md"), []byte("# Schemes\n"), 0o644))
+
+	out := &bytes.Buffer{}
+	names, err := ImportDir(out, schemes, themesDir, importOptions{name: "base16"}, &themes.AltieTheme{})
+	c.NoError(err)
+	c.Equal([]string{"base16/dracula.base24.toml", "base16/nord.base16.toml"}, names)
+	c.Contains(out.String(), "Skipped broken.yaml: broken.yaml: theme is missing a color: missing base05\n")
+	c.Contains(out.String(), "Imported 2 themes from "+schemes+", skipped 1\n")
+
+	dracula, err := themes.LoadPalette(filepath.Join(themesDir, "base16", "dracula.base24.toml"))
+	c.NoError(err)
+	c.Equal("#ff92df", dracula.Bright[5].Hex())
+
+	// Forcing a format tries every file
+	out.Reset()
+	names, err = ImportDir(out, schemes, themesDir, importOptions{format: formats.Base16, force: true}, &themes.AltieTheme{})
+	c.NoError(err)
+	c.Len(names, 2)
+	c.Contains(out.String(), "Skipped README.md")
+	c.Contains(out.String(), "skipped 2\n")
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/pterm/pterm"
+)
+
+// List prints the themes matching filter, one per line, or a table of
+// what the index knows about them when long is set.
+func List(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, filter themes.Filter, long bool) error {
+	index, err := loadIndex(altieConfig, appConfig)
+	if err != nil {
+		return err
+	}
+
+	entries := index.Entries(filter)
+	if !long {
+		for _, entry := range entries {
+			fmt.Fprintln(w, entry.Name)
+		}
+
+		return nil
+	}
+
+	data := [][]string{{"Theme", "Variant", "Contrast", "Source", "Modified"}}
+	for _, entry := range entries {
+		contrast := ""
+		if entry.Palette != nil {
+			contrast = fmt.Sprintf("%.1f:1", entry.Contrast)
+		}
+
+		data = append(data, []string{
+			entry.Name,
+			string(entry.Variant),
+			contrast,
+			entry.Source,
+			entry.ModTime.Format(time.DateTime),
+		})
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithWriter(w).WithData(data).Render()
+}
+
+func runList(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	parseFilter := addFilterFlags(flags)
+	long := flags.Bool("long", false, "show the variant, contrast, source and modification time")
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter()
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	return List(os.Stdout, altieConfig, appConfig, filter, *long)
+}
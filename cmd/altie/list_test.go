@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	appConfig := config.NewAppConfig(tmpDir)
+	c.NoError(os.MkdirAll(appConfig.ThemesDir, os.ModePerm))
+
+	for _, name := range []string{"Nord.toml", "Solarized-Light.toml"} {
+		content, err := os.ReadFile(filepath.Join("..", "..", "themes", name))
+		c.NoError(err)
+		c.NoError(os.WriteFile(filepath.Join(appConfig.ThemesDir, name), content, 0o644))
+	}
+
+	configThemes := &config.ConfigThemes{
+		Config: config.Config{
+			ThemesDirectory: appConfig.ThemesDir,
+		},
+	}
+
+	out := &bytes.Buffer{}
+	err = List(out, configThemes, appConfig, themes.Filter{}, false)
+	c.NoError(err)
+	c.Equal("Nord.toml\nSolarized-Light.toml\n", out.String())
+
+	out.Reset()
+	err = List(out, configThemes, appConfig, themes.Filter{Variant: palette.Dark}, false)
+	c.NoError(err)
+	c.Equal("Nord.toml\n", out.String())
+
+	out.Reset()
+	err = List(out, configThemes, appConfig, themes.Filter{Variant: palette.Light}, false)
+	c.NoError(err)
+	c.Equal("Solarized-Light.toml\n", out.String())
+
+	_, err = os.Stat(appConfig.IndexPath)
+	c.NoError(err)
+
+	out.Reset()
+	err = List(out, configThemes, appConfig, themes.Filter{}, true)
+	c.NoError(err)
+	c.Contains(out.String(), "Modified")
+	c.Contains(out.String(), "light")
+	c.Contains(out.String(), "9.2:1")
+	c.Contains(out.String(), themes.SourceLocal)
+
+	err = markSource(configThemes, appConfig, themes.SourceGithub)
+	c.NoError(err)
+
+	out.Reset()
+	err = List(out, configThemes, appConfig, themes.Filter{}, true)
+	c.NoError(err)
+	c.Contains(out.String(), themes.SourceGithub)
+	c.NotContains(out.String(), themes.SourceLocal)
+}
+
+func TestRunListInvalidVariant(t *testing.T) {
+	c := require.New(t)
+
+	err := run([]string{"list", "--variant", "night"})
+	c.ErrorIs(err, palette.ErrInvalidVariant)
+}
@@ -1,53 +1,120 @@
 package main
 
 import (
-	"fmt"
+	"flag"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/picker"
 	"github.com/copydataai/altie/internal/themes"
 	"github.com/hackebrot/turtle"
-	cp "github.com/otiai10/copy"
 	"github.com/pterm/pterm"
 )
 
-func ListThemes(altieConfig *config.ConfigThemes, appConfig *config.AppConfig) error {
-	dirs, err := themes.ListThemes(altieConfig.Config.ThemesDirectory)
+func ListThemes(altieConfig *config.ConfigThemes, appConfig *config.AppConfig, filter themes.Filter) error {
+	state, err := config.LoadState(appConfig.StatePath)
 	if err != nil {
 		return err
 	}
 
-	selectedOption, err := pterm.DefaultInteractiveSelect.WithOptions(dirs).Show()
+	index, err := loadIndex(altieConfig, appConfig)
 	if err != nil {
 		return err
 	}
 
-	path := fmt.Sprintf(altieConfig.Config.ThemesDirectory+"/%s", selectedOption)
-	pterm.Info.Println(path)
-
-	backupTheme, err := themes.BackUpTheme(appConfig.AlacrittyConfig)
+	selected, err := picker.New("Select a theme", pickerItems(index, state, filter)).Run()
 	if err != nil {
 		return err
 	}
 
-	pterm.Info.Printfln("The last theme was saved as %s", backupTheme)
+	selectedOption := selected.Name
 
-	err = cp.Copy(path, appConfig.AlacrittyConfig)
+	pterm.Info.Println(filepath.Join(altieConfig.Config.ThemesDirectory, filepath.FromSlash(selectedOption)))
+
+	backupTheme, err := applyTheme(os.Stdout, altieConfig, appConfig, state, selectedOption)
 	if err != nil {
 		return err
 	}
 
-	err = themes.ApplyFontTheme(appConfig.AlacrittyConfig, &altieConfig.ThemeConfig)
+	pterm.Info.Printfln("The last theme was saved as %s", backupTheme)
+	pterm.Success.Printfln("Selected option: %s has been applied successful", pterm.Green(selectedOption))
+
+	return nil
+}
+
+func loadIndex(altieConfig *config.ConfigThemes, appConfig *config.AppConfig) (*themes.Index, error) {
+	return themes.LoadIndex(appConfig.IndexPath, altieConfig.Config.ThemesDirectory)
+}
+
+// markSource records in the index where the named themes come from,
+// every theme when no name is given.
+func markSource(altieConfig *config.ConfigThemes, appConfig *config.AppConfig, source string, names ...string) error {
+	index, err := loadIndex(altieConfig, appConfig)
 	if err != nil {
 		return err
 	}
 
-	pterm.Success.Printfln("Selected option: %s has been applied successful", pterm.Green(selectedOption))
+	if len(names) == 0 {
+		for _, entry := range index.Themes {
+			names = append(names, entry.Name)
+		}
+	}
 
-	return nil
+	index.SetSource(source, names...)
+
+	return index.Save(appConfig.IndexPath)
+}
+
+// recentThemes is how many recently applied themes the picker offers on top.
+const recentThemes = 5
+
+// pickerItems lists the themes of the index matching filter, the
+// favorites first, then the recently applied ones, then the rest grouped
+// by category.
+func pickerItems(index *themes.Index, state *config.State, filter themes.Filter) []picker.Item {
+	matching := index.Entries(filter)
+	entries := make(map[string]themes.IndexEntry, len(matching))
+	for _, entry := range matching {
+		entries[entry.Name] = entry
+	}
+
+	items := make([]picker.Item, 0, len(entries))
+	added := make(map[string]bool, len(entries))
+	add := func(name string, section picker.Section) {
+		entry, ok := entries[name]
+		if !ok || added[name] {
+			return
+		}
+
+		added[name] = true
+		items = append(items, picker.Item{
+			Name:    name,
+			Variant: entry.Variant,
+			Tags:    state.Tags[name],
+			Section: section,
+		})
+	}
+
+	for _, name := range state.Favorites {
+		add(name, picker.SectionFavorites)
+	}
+
+	for _, name := range state.Recent(recentThemes) {
+		add(name, picker.SectionRecent)
+	}
+
+	for _, entry := range matching {
+		add(entry.Name, picker.SectionThemes)
+	}
+
+	return items
 }
 
-func CreateConfig() error {
+func CreateConfig(filter themes.Filter) error {
 	homeDir, err := config.GetHomeDir()
 	if err != nil {
 		return err
@@ -97,10 +164,15 @@ func CreateConfig() error {
 			return err
 		}
 
+		err = markSource(altieConfig, appConfig, themes.SourceGithub)
+		if err != nil {
+			return err
+		}
+
 		pterm.Info.Printfln("it's created the themes in %s/.altie/themes", homeDir)
 	}
 
-	err = ListThemes(altieConfig, appConfig)
+	err = ListThemes(altieConfig, appConfig, filter)
 	if err != nil {
 		return err
 	}
@@ -108,11 +180,181 @@ func CreateConfig() error {
 	return nil
 }
 
-func main() {
+// loadConfig reads altie.conf for the commands that don't create it.
+func loadConfig() (*config.ConfigThemes, *config.AppConfig, error) {
+	homeDir, err := config.GetHomeDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	appConfig := config.NewAppConfig(homeDir)
+
+	altieConfig, err := config.CheckConfig(appConfig.ConfigFilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return altieConfig, appConfig, nil
+}
+
+// loadState reads the favorites, tags and history of altie.
+func loadState() (*config.State, error) {
+	homeDir, err := config.GetHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.LoadState(config.NewAppConfig(homeDir).StatePath)
+}
+
+// themeName resolves a theme given on the command line to its name in
+// the index.
+func themeName(altieConfig *config.ConfigThemes, name string) (string, error) {
+	themePath, err := themes.ResolveTheme(altieConfig.Config.ThemesDirectory, name)
+	if err != nil {
+		return "", err
+	}
+
+	relative, err := filepath.Rel(altieConfig.Config.ThemesDirectory, themePath)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(relative), nil
+}
+
+// favoritesTag is the tag naming the favorite themes.
+const favoritesTag = "fav"
+
+// selectThemes lists the favorite themes, the ones tagged with tag or
+// the favorites tagged with it when both are asked.
+func selectThemes(state *config.State, favorites bool, tag string) []string {
+	// The favorites answer to the fav tag too, as in altie random --tag fav
+	if tag == favoritesTag {
+		favorites, tag = true, ""
+	}
+
+	if !favorites {
+		return state.Tagged(tag)
+	}
+
+	names := make([]string, 0, len(state.Favorites))
+	for _, name := range state.Favorites {
+		if tag == "" || slices.Contains(state.Tags[name], tag) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// parseFlags parses args allowing flags after the positional arguments,
+// as in "altie contrast Nord --apca", and returns the positional ones.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	positional := make([]string, 0)
+	for {
+		err := flags.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// addFilterFlags registers the flags shared by every command that filters themes.
+func addFilterFlags(flags *flag.FlagSet) func() (themes.Filter, error) {
+	variant := flags.String("variant", "", "only show dark or light themes")
+	minContrast := flags.Float64("min-contrast", 0, "only show themes whose foreground reaches this WCAG contrast ratio")
+	all := flags.Bool("all", false, "also show the duplicates hidden by altie dedupe")
+	category := flags.String("category", "", "only show the themes of a subdirectory of the themes directory")
+	favorites := flags.Bool("fav", false, "only show the favorite themes")
+	tag := flags.String("tag", "", "only show the themes with this tag")
+
+	return func() (themes.Filter, error) {
+		filter := themes.Filter{MinContrast: *minContrast, IncludeHidden: *all, Category: *category}
+		if *variant != "" {
+			v, err := palette.ParseVariant(*variant)
+			if err != nil {
+				return filter, err
+			}
+			filter.Variant = v
+		}
+
+		if *favorites || *tag != "" {
+			state, err := loadState()
+			if err != nil {
+				return filter, err
+			}
+			filter.Names = selectThemes(state, *favorites, strings.TrimPrefix(*tag, "#"))
+		}
+
+		return filter, nil
+	}
+}
+
+func runSelect(args []string) error {
+	flags := flag.NewFlagSet("altie", flag.ContinueOnError)
+	parseFilter := addFilterFlags(flags)
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter()
+	if err != nil {
+		return err
+	}
+
 	pterm.Printfln("Welcome to Altie \nan alternative version of alacritty-themes\nhas been building with Go %s", turtle.Emojis["bear"])
 
-	err := CreateConfig()
+	return CreateConfig(filter)
+}
+
+var commands = map[string]func(args []string) error{
+	"apply":         runApply,
+	"list":          runList,
+	"contrast":      runContrast,
+	"fix-contrast":  runFixContrast,
+	"cvd":           runCVD,
+	"similar":       runSimilar,
+	"search":        runSearch,
+	"dedupe":        runDedupe,
+	"tui":           runTUI,
+	"fav":           runFav,
+	"tag":           runTag,
+	"random":        runRandom,
+	"schedule":      runSchedule,
+	"auto":          runAuto,
+	"daemon":        runDaemon,
+	"follow-system": runFollowSystem,
+	"export":        runExport,
+	"import":        runImport,
+	"fonts":         runFonts,
+	"font":          runFont,
+}
+
+func run(args []string) error {
+	if len(args) > 0 {
+		if command, ok := commands[args[0]]; ok {
+			return command(args[1:])
+		}
+	}
+
+	return runSelect(args)
+}
+
+func main() {
+	err := run(os.Args[1:])
 	if err != nil {
 		pterm.Error.PrintOnError(err)
+		os.Exit(1)
 	}
 }
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -10,6 +12,8 @@ import (
 	"atomicgo.dev/keyboard/keys"
 	"github.com/BurntSushi/toml"
 	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/fonts"
+	"github.com/copydataai/altie/internal/picker"
 	"github.com/copydataai/altie/internal/themes"
 	"github.com/stretchr/testify/require"
 )
@@ -22,7 +26,7 @@ func TestCreateConfig(t *testing.T) {
 	err = os.Unsetenv("HOME")
 	c.NoError(err)
 
-	err = CreateConfig()
+	err = CreateConfig(themes.Filter{})
 	c.Error(err)
 
 	err = os.Setenv("HOME", tmpDir)
@@ -32,7 +36,7 @@ func TestCreateConfig(t *testing.T) {
 		keyboard.SimulateKeyPress(keys.Enter)
 	}()
 
-	err = CreateConfig()
+	err = CreateConfig(themes.Filter{})
 	c.NoError(err)
 
 	go func() {
@@ -41,7 +45,7 @@ func TestCreateConfig(t *testing.T) {
 		keyboard.SimulateKeyPress(keys.Enter)
 	}()
 
-	err = CreateConfig()
+	err = CreateConfig(themes.Filter{})
 	c.NoError(err)
 
 	err = os.RemoveAll(tmpDir)
@@ -80,7 +84,7 @@ func TestCreateConfig(t *testing.T) {
 
 	// Error when a altie.conf doesn't exists
 	// But the directory exists
-	err = CreateConfig()
+	err = CreateConfig(themes.Filter{})
 	c.Error(err)
 }
 
@@ -121,7 +125,7 @@ func TestListThemes(t *testing.T) {
 			Font:     "",
 		},
 	}
-	err = ListThemes(configThemes, appConfig)
+	err = ListThemes(configThemes, appConfig, themes.Filter{})
 	c.Error(err)
 
 	go func() {
@@ -130,7 +134,7 @@ func TestListThemes(t *testing.T) {
 		keyboard.SimulateKeyPress(keys.Enter)
 	}()
 
-	err = ListThemes(configThemes, appConfig)
+	err = ListThemes(configThemes, appConfig, themes.Filter{})
 	c.Error(err)
 	c.True(os.IsNotExist(err))
 
@@ -151,19 +155,36 @@ func TestListThemes(t *testing.T) {
 
 	fakeAppConfig := config.NewAppConfig("//")
 
-	err = ListThemes(configThemes, fakeAppConfig)
+	err = ListThemes(configThemes, fakeAppConfig, themes.Filter{})
 	c.Error(err)
 	fmt.Println(err.Error())
 	c.True(os.IsNotExist(err))
 
 	configThemes.Config.ThemesDirectory = appConfig.ThemesDir + "/fake"
-	err = ListThemes(configThemes, appConfig)
+	err = ListThemes(configThemes, appConfig, themes.Filter{})
 
 	c.Error(err)
 	c.EqualError(err, fmt.Errorf("no options provided").Error())
 
 }
 
+func TestParseFlags(t *testing.T) {
+	c := require.New(t)
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	apca := flags.Bool("apca", false, "")
+	target := flags.Float64("target", 0, "")
+
+	positional, err := parseFlags(flags, []string{"Nord", "--apca", "Dracula", "--target", "7"})
+	c.NoError(err)
+	c.Equal([]string{"Nord", "Dracula"}, positional)
+	c.True(*apca)
+	c.Equal(7.0, *target)
+
+	_, err = parseFlags(flags, []string{"Nord", "--unknown"})
+	c.Error(err)
+}
+
 func TestApplyFontTheme(t *testing.T) {
 	c := require.New(t)
 
@@ -200,6 +221,8 @@ func TestApplyFontTheme(t *testing.T) {
 	err = os.MkdirAll(appConfig.AlacrittyDir, os.ModePerm)
 	c.NoError(err)
 
+	cacheFaces(c, appConfig, []fonts.Face{{Family: "SpaceMono Nerd Font", Style: "Regular", Monospace: true}})
+
 	f, err := os.Create(appConfig.AlacrittyConfig)
 	c.NoError(err)
 
@@ -217,9 +240,13 @@ func TestApplyFontTheme(t *testing.T) {
 		keyboard.SimulateKeyPress(keys.Enter)
 	}()
 
-	err = ListThemes(configThemes, appConfig)
+	err = ListThemes(configThemes, appConfig, themes.Filter{})
 	c.NoError(err)
 
+	state, err := config.LoadState(appConfig.StatePath)
+	c.NoError(err)
+	c.Len(state.Recent(0), 1)
+
 	f, err = os.Open(appConfig.ConfigDir)
 	c.NoError(err)
 
@@ -236,13 +263,58 @@ func TestApplyFontTheme(t *testing.T) {
 
 	fakeAppConfig := config.NewAppConfig("//")
 
-	err = ListThemes(configThemes, fakeAppConfig)
+	err = ListThemes(configThemes, fakeAppConfig, themes.Filter{})
 	c.Error(err)
 	fmt.Println(err.Error())
 	c.True(os.IsNotExist(err))
 
 	configThemes.Config.ThemesDirectory = appConfig.ThemesDir + "/fake"
-	err = ListThemes(configThemes, appConfig)
+	err = ListThemes(configThemes, appConfig, themes.Filter{})
 	c.Error(err)
 	c.EqualError(err, fmt.Errorf("no options provided").Error())
 }
+
+func TestPickerItems(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	appConfig := config.NewAppConfig(tmpDir)
+
+	for _, name := range []string{"Afterglow.toml", "Nord.toml", "catppuccin/Mocha.toml", "Dracula.toml"} {
+		path := filepath.Join(appConfig.ThemesDir, filepath.FromSlash(name))
+		c.NoError(os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		c.NoError(os.WriteFile(path, nil, 0o644))
+	}
+
+	configThemes := &config.ConfigThemes{
+		Config: config.Config{
+			ThemesDirectory: appConfig.ThemesDir,
+		},
+	}
+
+	state := &config.State{
+		Favorites: []string{"Nord.toml", "Missing.toml"},
+		Tags:      map[string][]string{"catppuccin/Mocha.toml": {"work"}},
+	}
+	state.AddHistory("Nord.toml", time.Now())
+	state.AddHistory("catppuccin/Mocha.toml", time.Now())
+
+	index, err := loadIndex(configThemes, appConfig)
+	c.NoError(err)
+
+	items := pickerItems(index, state, themes.Filter{})
+	c.Equal([]picker.Item{
+		{Name: "Nord.toml", Section: picker.SectionFavorites},
+		{Name: "catppuccin/Mocha.toml", Tags: []string{"work"}, Section: picker.SectionRecent},
+		{Name: "Afterglow.toml", Section: picker.SectionThemes},
+		{Name: "Dracula.toml", Section: picker.SectionThemes},
+	}, items)
+
+	items = pickerItems(index, state, themes.Filter{Category: "catppuccin"})
+	c.Len(items, 1)
+	c.Equal(picker.SectionRecent, items[0].Section)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/themes"
+)
+
+var ErrNoRandomTheme = errors.New("no theme left to pick from")
+
+// pickRandom picks one of names at random, leaving out the recent ones.
+func pickRandom(names []string, recent []string, rng *rand.Rand) (string, error) {
+	excluded := make(map[string]bool, len(recent))
+	for _, name := range recent {
+		excluded[name] = true
+	}
+
+	candidates := make([]string, 0, len(names))
+	for _, name := range names {
+		if !excluded[name] {
+			candidates = append(candidates, name)
+		}
+	}
+
+	switch {
+	case len(names) == 0:
+		return "", fmt.Errorf("%w: no theme matches", ErrNoRandomTheme)
+	case len(candidates) == 0:
+		return "", fmt.Errorf("%w: the %d matching themes were all applied recently", ErrNoRandomTheme, len(names))
+	}
+
+	return candidates[rng.Intn(len(candidates))], nil
+}
+
+// Random applies a theme matching filter picked with rng, other than the
+// excludeRecent last applied ones, and returns its name.
+func Random(w io.Writer, altieConfig *config.ConfigThemes, appConfig *config.AppConfig, filter themes.Filter, excludeRecent int, rng *rand.Rand) (string, error) {
+	state, err := config.LoadState(appConfig.StatePath)
+	if err != nil {
+		return "", err
+	}
+
+	index, err := loadIndex(altieConfig, appConfig)
+	if err != nil {
+		return "", err
+	}
+
+	recent := make([]string, 0)
+	if excludeRecent > 0 {
+		recent = state.Recent(excludeRecent)
+	}
+
+	// Themes whose palette couldn't be read would fail to apply
+	names := make([]string, 0)
+	for _, entry := range index.Entries(filter) {
+		if entry.Palette != nil {
+			names = append(names, entry.Name)
+		}
+	}
+
+	name, err := pickRandom(names, recent, rng)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = applyTheme(w, altieConfig, appConfig, state, name)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(w, "Applied %s\n", name)
+
+	return name, nil
+}
+
+func runRandom(args []string) error {
+	flags := flag.NewFlagSet("random", flag.ContinueOnError)
+	parseFilter := addFilterFlags(flags)
+	excludeRecent := flags.Int("exclude-recent", 0, "leave out this many of the last applied themes")
+	seed := flags.Int64("seed", 0, "pick with this seed, the same seed picks the same theme")
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter()
+	if err != nil {
+		return err
+	}
+
+	seeded := false
+	flags.Visit(func(f *flag.Flag) {
+		seeded = seeded || f.Name == "seed"
+	})
+
+	if !seeded {
+		*seed = time.Now().UnixNano()
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	_, err = Random(os.Stdout, altieConfig, appConfig, filter, *excludeRecent, rand.New(rand.NewSource(*seed)))
+	return err
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickRandom(t *testing.T) {
+	c := require.New(t)
+
+	names := []string{"Afterglow.toml", "Dracula.toml", "Nord.toml", "Solarized.toml"}
+
+	// The same seed picks the same theme
+	first, err := pickRandom(names, nil, rand.New(rand.NewSource(7)))
+	c.NoError(err)
+	second, err := pickRandom(names, nil, rand.New(rand.NewSource(7)))
+	c.NoError(err)
+	c.Equal(first, second)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		name, err := pickRandom(names, []string{"Dracula.toml", "Nord.toml", "Solarized.toml"}, rng)
+		c.NoError(err)
+		c.Equal("Afterglow.toml", name)
+	}
+
+	_, err = pickRandom(names, names, rng)
+	c.ErrorIs(err, ErrNoRandomTheme)
+
+	_, err = pickRandom(nil, nil, rng)
+	c.ErrorIs(err, ErrNoRandomTheme)
+}
+
+func TestRandom(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	appConfig := config.NewAppConfig(tmpDir)
+	c.NoError(config.CreateConfig(appConfig))
+	c.NoError(os.MkdirAll(appConfig.ThemesDir, os.ModePerm))
+	c.NoError(os.MkdirAll(appConfig.AlacrittyDir, os.ModePerm))
+	c.NoError(os.WriteFile(appConfig.AlacrittyConfig, nil, 0o644))
+
+	for _, name := range []string{"Nord.toml", "Solarized-Light.toml", "Dracula.toml"} {
+		content, err := os.ReadFile(filepath.Join("..", "..", "themes", name))
+		c.NoError(err)
+		c.NoError(os.WriteFile(filepath.Join(appConfig.ThemesDir, name), content, 0o644))
+	}
+
+	// A theme without a palette is never picked
+	c.NoError(os.WriteFile(filepath.Join(appConfig.ThemesDir, "Broken.toml"), []byte("[colors"), 0o644))
+
+	altieConfig, err := config.CheckConfig(appConfig.ConfigFilePath)
+	c.NoError(err)
+
+	out := &bytes.Buffer{}
+	name, err := Random(out, altieConfig, appConfig, themes.Filter{Variant: palette.Light}, 0, rand.New(rand.NewSource(1)))
+	c.NoError(err)
+	c.Equal("Solarized-Light.toml", name)
+	c.Equal("Applied Solarized-Light.toml\n", out.String())
+
+	// Excluding the recent themes rotates through the dark ones
+	applied := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		name, err = Random(out, altieConfig, appConfig, themes.Filter{Variant: palette.Dark}, 2, rand.New(rand.NewSource(1)))
+		c.NoError(err)
+		applied[name] = true
+	}
+	c.Equal(map[string]bool{"Nord.toml": true, "Dracula.toml": true}, applied)
+
+	_, err = Random(out, altieConfig, appConfig, themes.Filter{Variant: palette.Dark}, 2, rand.New(rand.NewSource(1)))
+	c.ErrorIs(err, ErrNoRandomTheme)
+
+	// Broken.toml matches no variant but would match an empty filter
+	_, err = Random(out, altieConfig, appConfig, themes.Filter{}, 3, rand.New(rand.NewSource(1)))
+	c.ErrorIs(err, ErrNoRandomTheme)
+
+	state, err := config.LoadState(appConfig.StatePath)
+	c.NoError(err)
+	c.Len(state.History, 3)
+}
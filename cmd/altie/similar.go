@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/pterm/pterm"
+)
+
+var ErrMissingSearchColor = errors.New("search needs --bg or --fg")
+
+// printMatches renders the closest matches along with their primary colors.
+func printMatches(w io.Writer, index *themes.Index, matches []themes.Match, limit int) error {
+	palettes := make(map[string]*palette.Palette, len(index.Themes))
+	for _, entry := range index.Themes {
+		palettes[entry.Name] = entry.Palette
+	}
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	data := [][]string{{"Theme", "ΔE", "Background", "Foreground"}}
+	for _, match := range matches {
+		themePalette := palettes[match.Name]
+		data = append(data, []string{
+			match.Name,
+			fmt.Sprintf("%.1f", match.Distance),
+			swatch(themePalette.Background),
+			swatch(themePalette.Foreground),
+		})
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithWriter(w).WithData(data).Render()
+}
+
+// Similar prints the themes whose palette is closest to the theme in themePath.
+func Similar(w io.Writer, index *themes.Index, themePath string, filter themes.Filter, limit int) ([]themes.Match, error) {
+	target, err := themes.LoadPalette(themePath)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := filepath.Rel(index.ThemesDirectory, themePath)
+	if err != nil {
+		return nil, err
+	}
+	name = filepath.ToSlash(name)
+
+	matches := make([]themes.Match, 0)
+	for _, match := range index.Rank(filter, func(p *palette.Palette) float64 { return palette.Distance(target, p) }) {
+		if match.Name != name {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches, printMatches(w, index, matches, limit)
+}
+
+// Search prints the themes whose primary colors are closest to the given ones,
+// a nil color isn't compared.
+func Search(w io.Writer, index *themes.Index, background, foreground *palette.Color, filter themes.Filter, limit int) ([]themes.Match, error) {
+	if background == nil && foreground == nil {
+		return nil, ErrMissingSearchColor
+	}
+
+	matches := index.Rank(filter, func(p *palette.Palette) float64 {
+		distance := 0.0
+		if background != nil {
+			distance += palette.DeltaE(*background, p.Background)
+		}
+
+		if foreground != nil {
+			distance += palette.DeltaE(*foreground, p.Foreground)
+		}
+
+		if background != nil && foreground != nil {
+			distance /= 2
+		}
+
+		return distance
+	})
+
+	return matches, printMatches(w, index, matches, limit)
+}
+
+func runSimilar(args []string) error {
+	flags := flag.NewFlagSet("similar", flag.ContinueOnError)
+	parseFilter := addFilterFlags(flags)
+	limit := flags.Int("limit", 10, "how many themes to show, 0 shows them all")
+
+	names, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(names) != 1 {
+		return fmt.Errorf("%w: altie similar [flags] <theme>", ErrMissingTheme)
+	}
+
+	filter, err := parseFilter()
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	themePath, err := themes.ResolveTheme(altieConfig.Config.ThemesDirectory, names[0])
+	if err != nil {
+		return err
+	}
+
+	index, err := loadIndex(altieConfig, appConfig)
+	if err != nil {
+		return err
+	}
+
+	_, err = Similar(os.Stdout, index, themePath, filter, *limit)
+	return err
+}
+
+// colorFlag is a flag holding an optional hex color.
+type colorFlag struct {
+	color *palette.Color
+}
+
+func (cf *colorFlag) String() string {
+	if cf.color == nil {
+		return ""
+	}
+
+	return cf.color.Hex()
+}
+
+func (cf *colorFlag) Set(value string) error {
+	color, err := palette.ParseHex(value)
+	if err != nil {
+		return err
+	}
+
+	cf.color = &color
+
+	return nil
+}
+
+func runSearch(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	parseFilter := addFilterFlags(flags)
+	limit := flags.Int("limit", 10, "how many themes to show, 0 shows them all")
+	background, foreground := &colorFlag{}, &colorFlag{}
+	flags.Var(background, "bg", "background color to look for, as #rrggbb")
+	flags.Var(foreground, "fg", "foreground color to look for, as #rrggbb")
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter()
+	if err != nil {
+		return err
+	}
+
+	if background.color == nil && foreground.color == nil {
+		return ErrMissingSearchColor
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	index, err := loadIndex(altieConfig, appConfig)
+	if err != nil {
+		return err
+	}
+
+	_, err = Search(os.Stdout, index, background.color, foreground.color, filter, *limit)
+	return err
+}
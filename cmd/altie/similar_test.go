@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimilarSearch(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(themesDir, os.ModePerm))
+
+	for _, name := range []string{"Nord.toml", "Ocean.dark.toml", "Solarized-Light.toml", "Catppuccin-Mocha.toml"} {
+		content, err := os.ReadFile(filepath.Join("..", "..", "themes", name))
+		c.NoError(err)
+		c.NoError(os.WriteFile(filepath.Join(themesDir, name), content, 0o644))
+	}
+
+	index, err := themes.LoadIndex(filepath.Join(tmpDir, "index.json"), themesDir)
+	c.NoError(err)
+
+	out := &bytes.Buffer{}
+	matches, err := Similar(out, index, filepath.Join(themesDir, "Nord.toml"), themes.Filter{}, 2)
+	c.NoError(err)
+	c.Len(matches, 3)
+	c.Equal("Ocean.dark.toml", matches[0].Name)
+	c.Equal("Solarized-Light.toml", matches[2].Name)
+	c.Contains(out.String(), "Ocean.dark.toml")
+	c.NotContains(out.String(), "Solarized-Light.toml")
+	c.NotContains(out.String(), "Nord.toml")
+
+	matches, err = Similar(out, index, filepath.Join(themesDir, "Nord.toml"), themes.Filter{Variant: palette.Light}, 0)
+	c.NoError(err)
+	c.Len(matches, 1)
+
+	_, err = Similar(out, index, filepath.Join(themesDir, "NonExisting.toml"), themes.Filter{}, 0)
+	c.Error(err)
+
+	mocha, err := palette.ParseHex("#1e1e2e")
+	c.NoError(err)
+
+	out.Reset()
+	matches, err = Search(out, index, &mocha, nil, themes.Filter{}, 1)
+	c.NoError(err)
+	c.Equal("Catppuccin-Mocha.toml", matches[0].Name)
+	c.Zero(matches[0].Distance)
+	c.Contains(out.String(), "#1e1e2e")
+
+	white, err := palette.ParseHex("#ffffff")
+	c.NoError(err)
+
+	matches, err = Search(out, index, &mocha, &white, themes.Filter{}, 0)
+	c.NoError(err)
+	c.Len(matches, 4)
+	c.Greater(matches[0].Distance, 0.0)
+
+	_, err = Search(out, index, nil, nil, themes.Filter{}, 0)
+	c.ErrorIs(err, ErrMissingSearchColor)
+}
+
+func TestRunSimilarSearchArgs(t *testing.T) {
+	c := require.New(t)
+
+	err := run([]string{"similar"})
+	c.ErrorIs(err, ErrMissingTheme)
+
+	err = run([]string{"search"})
+	c.ErrorIs(err, ErrMissingSearchColor)
+
+	err = run([]string{"search", "--bg", "None"})
+	c.ErrorContains(err, palette.ErrInvalidColor.Error())
+}
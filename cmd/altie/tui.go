@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"slices"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/render"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/copydataai/altie/internal/tui"
+)
+
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// minFontSize keeps the font readable however many times it's shrunk.
+const minFontSize = 4
+
+// tuiChange is what a TUI action replaced, so it can be undone.
+type tuiChange struct {
+	description string
+	alacritty   []byte
+	fontSize    int64
+	outputs     []render.Rendered
+	history     []config.HistoryEntry
+}
+
+// tuiActions changes the configuration for the TUI through the code paths
+// of the CLI, remembering each change to undo it.
+type tuiActions struct {
+	altieConfig *config.ConfigThemes
+	appConfig   *config.AppConfig
+	state       *config.State
+	changes     []tuiChange
+}
+
+// remember saves the alacritty config, the font size, the outputs of the
+// templates and the history before a change.
+func (a *tuiActions) remember(description string) error {
+	content, err := os.ReadFile(a.appConfig.AlacrittyConfig)
+	if err != nil {
+		return err
+	}
+
+	outputs, err := render.Snapshot(templateTargets(a.altieConfig, a.appConfig))
+	if err != nil {
+		return err
+	}
+
+	a.changes = append(a.changes, tuiChange{description, content, a.altieConfig.FontSize, outputs, slices.Clone(a.state.History)})
+
+	return nil
+}
+
+// forget drops the last change after it failed.
+func (a *tuiActions) forget() {
+	a.changes = a.changes[:len(a.changes)-1]
+}
+
+// Apply applies the theme and returns what the hooks printed, the TUI
+// shows it in its status line.
+func (a *tuiActions) Apply(name string) (string, error) {
+	err := a.remember("applying " + name)
+	if err != nil {
+		return "", err
+	}
+
+	output := &bytes.Buffer{}
+	_, err = applyTheme(output, a.altieConfig, a.appConfig, a.state, name)
+	if err != nil {
+		a.forget()
+	}
+
+	return output.String(), err
+}
+
+func (a *tuiActions) ToggleFavorite(name string) (bool, error) {
+	favorite := a.state.ToggleFavorite(name)
+
+	err := a.state.Save(a.appConfig.StatePath)
+	if err != nil {
+		a.state.ToggleFavorite(name)
+		return !favorite, err
+	}
+
+	return favorite, nil
+}
+
+func (a *tuiActions) ChangeFontSize(delta int64) (int64, error) {
+	size := max(a.altieConfig.FontSize+delta, minFontSize)
+
+	err := a.remember("the font size change")
+	if err != nil {
+		return a.altieConfig.FontSize, err
+	}
+
+	err = a.setFontSize(size)
+	if err != nil {
+		a.forget()
+	}
+
+	return a.altieConfig.FontSize, err
+}
+
+// setFontSize applies size to the alacritty config and saves it in altie.conf
+// so the next themes keep it.
+func (a *tuiActions) setFontSize(size int64) error {
+	a.altieConfig.FontSize = size
+
+	err := themes.ApplyFontTheme(a.appConfig.AlacrittyConfig, &a.altieConfig.ThemeConfig)
+	if err != nil {
+		return err
+	}
+
+	return a.altieConfig.Save(a.appConfig.ConfigFilePath)
+}
+
+func (a *tuiActions) Undo() (string, error) {
+	if len(a.changes) == 0 {
+		return "", ErrNothingToUndo
+	}
+
+	change := a.changes[len(a.changes)-1]
+	a.forget()
+
+	err := os.WriteFile(a.appConfig.AlacrittyConfig, change.alacritty, 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	err = render.Save(change.outputs)
+	if err != nil {
+		return "", err
+	}
+
+	// The undone theme wasn't applied as far as the history goes
+	if !slices.Equal(change.history, a.state.History) {
+		a.state.History = change.history
+
+		err = a.state.Save(a.appConfig.StatePath)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if change.fontSize != a.altieConfig.FontSize {
+		a.altieConfig.FontSize = change.fontSize
+
+		err = a.altieConfig.Save(a.appConfig.ConfigFilePath)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return change.description, nil
+}
+
+// tuiThemes lists the themes for the TUI in the order of the picker.
+func tuiThemes(index *themes.Index, state *config.State, filter themes.Filter) []tui.Theme {
+	items := pickerItems(index, state, filter)
+	list := make([]tui.Theme, 0, len(items))
+	for _, item := range items {
+		entry, _ := index.Entry(item.Name)
+		list = append(list, tui.Theme{Item: item, Palette: entry.Palette})
+	}
+
+	return list
+}
+
+func runTUI(args []string) error {
+	flags := flag.NewFlagSet("tui", flag.ContinueOnError)
+	parseFilter := addFilterFlags(flags)
+
+	_, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter()
+	if err != nil {
+		return err
+	}
+
+	altieConfig, appConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	state, err := config.LoadState(appConfig.StatePath)
+	if err != nil {
+		return err
+	}
+
+	index, err := loadIndex(altieConfig, appConfig)
+	if err != nil {
+		return err
+	}
+
+	actions := &tuiActions{altieConfig: altieConfig, appConfig: appConfig, state: state}
+
+	return tui.New(tuiThemes(index, state, filter), actions).Run(os.Stdout)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTUIActions(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	appConfig := config.NewAppConfig(tmpDir)
+	c.NoError(config.CreateConfig(appConfig))
+	c.NoError(os.MkdirAll(appConfig.ThemesDir, os.ModePerm))
+	c.NoError(os.MkdirAll(appConfig.AlacrittyDir, os.ModePerm))
+
+	content, err := os.ReadFile(filepath.Join("..", "..", "themes", "Nord.toml"))
+	c.NoError(err)
+	c.NoError(os.WriteFile(filepath.Join(appConfig.ThemesDir, "Nord.toml"), content, 0o644))
+
+	original := []byte("[colors.primary]\nbackground = \"#000000\"\n")
+	c.NoError(os.WriteFile(appConfig.AlacrittyConfig, original, 0o644))
+
+	altieConfig, err := config.CheckConfig(appConfig.ConfigFilePath)
+	c.NoError(err)
+	altieConfig.ThemesDirectory = appConfig.ThemesDir
+
+	// The template output of the applied theme is undone with it
+	source := filepath.Join(tmpDir, "name.tmpl")
+	target := filepath.Join(tmpDir, "name.txt")
+	c.NoError(os.WriteFile(source, []byte("{{.Name}}"), 0o644))
+	c.NoError(os.WriteFile(target, []byte("Dracula"), 0o644))
+	altieConfig.Templates = []config.Template{{Source: source, Target: target}}
+
+	state := &config.State{}
+	state.AddHistory("Dracula.toml", time.Now())
+	actions := &tuiActions{altieConfig: altieConfig, appConfig: appConfig, state: state}
+
+	output, err := actions.Apply("Nord.toml")
+	c.NoError(err)
+	c.Empty(output)
+	c.Equal([]string{"Nord.toml", "Dracula.toml"}, state.Recent(0))
+
+	rendered, err := os.ReadFile(target)
+	c.NoError(err)
+	c.Equal("Nord", string(rendered))
+
+	applied, err := themes.CheckAlacrittyConfig(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Contains(applied, "colors")
+
+	size, err := actions.ChangeFontSize(2)
+	c.NoError(err)
+	c.Equal(altieConfig.FontSize, size)
+
+	saved, err := config.CheckConfig(appConfig.ConfigFilePath)
+	c.NoError(err)
+	c.Equal(size, saved.FontSize)
+
+	favorite, err := actions.ToggleFavorite("Nord.toml")
+	c.NoError(err)
+	c.True(favorite)
+
+	loaded, err := config.LoadState(appConfig.StatePath)
+	c.NoError(err)
+	c.Equal([]string{"Nord.toml"}, loaded.Favorites)
+
+	description, err := actions.Undo()
+	c.NoError(err)
+	c.Equal("the font size change", description)
+
+	saved, err = config.CheckConfig(appConfig.ConfigFilePath)
+	c.NoError(err)
+	c.Equal(size-2, saved.FontSize)
+
+	description, err = actions.Undo()
+	c.NoError(err)
+	c.Equal("applying Nord.toml", description)
+
+	restored, err := os.ReadFile(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Equal(original, restored)
+
+	rendered, err = os.ReadFile(target)
+	c.NoError(err)
+	c.Equal("Dracula", string(rendered))
+
+	loaded, err = config.LoadState(appConfig.StatePath)
+	c.NoError(err)
+	c.Equal([]string{"Dracula.toml"}, loaded.Recent(0))
+	c.Equal([]string{"Nord.toml"}, loaded.Favorites)
+
+	_, err = actions.Undo()
+	c.ErrorIs(err, ErrNothingToUndo)
+
+	// A failed apply leaves nothing to undo
+	_, err = actions.Apply("Missing.toml")
+	c.Error(err)
+	c.Empty(actions.changes)
+
+	// The hooks are heard, their failures too under the warn policy
+	altieConfig.Hooks = config.Hooks{PostApply: []string{"echo reloaded", "exit 3"}}
+	output, err = actions.Apply("Nord.toml")
+	c.NoError(err)
+	c.Equal("PostApply hook \"echo reloaded\":\nreloaded\nWarning: PostApply hook failed: exit 3: exit status 3\n", output)
+}
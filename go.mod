@@ -5,10 +5,13 @@ go 1.22
 require (
 	atomicgo.dev/keyboard v0.2.9
 	github.com/BurntSushi/toml v1.3.2
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/hackebrot/turtle v0.2.0
 	github.com/otiai10/copy v1.11.0
 	github.com/pterm/pterm v0.12.62
 	github.com/stretchr/testify v1.8.4
+	golang.org/x/sys v0.8.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -22,8 +25,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rivo/uniseg v0.4.4 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
-	golang.org/x/sys v0.8.0 // indirect
 	golang.org/x/term v0.8.0 // indirect
 	golang.org/x/text v0.9.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
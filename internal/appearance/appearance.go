@@ -0,0 +1,49 @@
+// Package appearance reads the light or dark preference of the desktop,
+// the org.freedesktop.appearance color-scheme setting of GNOME and KDE.
+package appearance
+
+import "github.com/copydataai/altie/internal/palette"
+
+// ColorScheme is the preference of the desktop for light or dark
+// applications.
+type ColorScheme uint32
+
+// Values of the color-scheme setting
+const (
+	NoPreference ColorScheme = iota
+	PreferDark
+	PreferLight
+)
+
+func (scheme ColorScheme) String() string {
+	switch scheme {
+	case PreferDark:
+		return "dark"
+	case PreferLight:
+		return "light"
+	default:
+		return "no preference"
+	}
+}
+
+// Variant returns the variant of themes matching the preference, light
+// without one as the desktops default to light.
+func (scheme ColorScheme) Variant() palette.Variant {
+	if scheme == PreferDark {
+		return palette.Dark
+	}
+
+	return palette.Light
+}
+
+// Settings reads and follows the color scheme of the desktop.
+type Settings interface {
+	// ColorScheme returns the current preference.
+	ColorScheme() (ColorScheme, error)
+
+	// Watch sends each new preference, the channel is closed when the
+	// settings are closed or can't be followed anymore.
+	Watch() (<-chan ColorScheme, error)
+
+	Close() error
+}
@@ -0,0 +1,144 @@
+package appearance
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// The settings of the desktop portal
+const (
+	portalDestination = "org.freedesktop.portal.Desktop"
+	portalPath        = dbus.ObjectPath("/org/freedesktop/portal/desktop")
+	settingsInterface = "org.freedesktop.portal.Settings"
+	settingChanged    = "SettingChanged"
+
+	appearanceNamespace = "org.freedesktop.appearance"
+	colorSchemeKey      = "color-scheme"
+)
+
+var ErrInvalidColorScheme = errors.New("the portal returned an invalid color scheme")
+
+// Portal reads the color scheme from the settings of the desktop portal
+// on the session D-Bus.
+type Portal struct {
+	conn *dbus.Conn
+	done chan struct{}
+	once sync.Once
+}
+
+// ConnectPortal connects to the portal of the session bus.
+func ConnectPortal() (*Portal, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the session bus: %w", err)
+	}
+
+	return NewPortal(conn), nil
+}
+
+// NewPortal reads the settings through conn, closed with the portal.
+func NewPortal(conn *dbus.Conn) *Portal {
+	return &Portal{conn: conn, done: make(chan struct{})}
+}
+
+func (p *Portal) ColorScheme() (ColorScheme, error) {
+	settings := p.conn.Object(portalDestination, portalPath)
+
+	var value dbus.Variant
+	err := settings.Call(settingsInterface+".ReadOne", 0, appearanceNamespace, colorSchemeKey).Store(&value)
+	if err != nil {
+		// ReadOne is only in the second version of the portal, Read
+		// wraps the value in another variant
+		err = settings.Call(settingsInterface+".Read", 0, appearanceNamespace, colorSchemeKey).Store(&value)
+		if err != nil {
+			return NoPreference, fmt.Errorf("failed to read the color scheme: %w", err)
+		}
+	}
+
+	return schemeFromVariant(value)
+}
+
+// schemeFromVariant unwraps the color scheme from the variants around it.
+func schemeFromVariant(value dbus.Variant) (ColorScheme, error) {
+	for {
+		switch v := value.Value().(type) {
+		case dbus.Variant:
+			value = v
+		case uint32:
+			if v > uint32(PreferLight) {
+				return NoPreference, fmt.Errorf("%w: %d", ErrInvalidColorScheme, v)
+			}
+
+			return ColorScheme(v), nil
+		default:
+			return NoPreference, fmt.Errorf("%w: %s", ErrInvalidColorScheme, value.Signature())
+		}
+	}
+}
+
+// schemeFromSignal returns the color scheme set by a SettingChanged
+// signal, false for the other signals and settings.
+func schemeFromSignal(signal *dbus.Signal) (ColorScheme, bool) {
+	if signal.Name != settingsInterface+"."+settingChanged || len(signal.Body) != 3 {
+		return NoPreference, false
+	}
+
+	namespace, _ := signal.Body[0].(string)
+	key, _ := signal.Body[1].(string)
+	value, ok := signal.Body[2].(dbus.Variant)
+	if namespace != appearanceNamespace || key != colorSchemeKey || !ok {
+		return NoPreference, false
+	}
+
+	scheme, err := schemeFromVariant(value)
+
+	return scheme, err == nil
+}
+
+func (p *Portal) Watch() (<-chan ColorScheme, error) {
+	err := p.conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(portalPath),
+		dbus.WithMatchInterface(settingsInterface),
+		dbus.WithMatchMember(settingChanged),
+		dbus.WithMatchArg(0, appearanceNamespace),
+		dbus.WithMatchArg(1, colorSchemeKey),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow the settings: %w", err)
+	}
+
+	signals := make(chan *dbus.Signal, 8)
+	p.conn.Signal(signals)
+
+	schemes := make(chan ColorScheme)
+	go func() {
+		defer close(schemes)
+
+		// The signals are closed with the connection
+		for signal := range signals {
+			scheme, ok := schemeFromSignal(signal)
+			if !ok {
+				continue
+			}
+
+			select {
+			case schemes <- scheme:
+			case <-p.done:
+				return
+			}
+		}
+	}()
+
+	return schemes, nil
+}
+
+func (p *Portal) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+	})
+
+	return p.conn.Close()
+}
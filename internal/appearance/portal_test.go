@@ -0,0 +1,159 @@
+package appearance
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSettings serves the color scheme like the portal, only with Read
+// when legacy is set.
+type fakeSettings struct {
+	mu     sync.Mutex
+	scheme uint32
+	legacy bool
+}
+
+func (s *fakeSettings) set(scheme ColorScheme, legacy bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scheme, s.legacy = uint32(scheme), legacy
+}
+
+func (s *fakeSettings) ReadOne(namespace, key string) (dbus.Variant, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.legacy {
+		return dbus.Variant{}, dbus.NewError("org.freedesktop.DBus.Error.UnknownMethod", nil)
+	}
+
+	return dbus.MakeVariant(s.scheme), nil
+}
+
+func (s *fakeSettings) Read(namespace, key string) (dbus.Variant, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return dbus.MakeVariant(dbus.MakeVariant(s.scheme)), nil
+}
+
+// privateBus starts a bus for the test and returns its address.
+func privateBus(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("dbus-daemon"); err != nil {
+		t.Skip("dbus-daemon isn't installed")
+	}
+
+	c := require.New(t)
+
+	configPath := filepath.Join(t.TempDir(), "bus.conf")
+	c.NoError(os.WriteFile(configPath, []byte(`<busconfig>
+  <type>session</type>
+  <listen>unix:tmpdir=`+os.TempDir()+`</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>`), 0o644))
+
+	daemon := exec.Command("dbus-daemon", "--config-file", configPath, "--print-address", "--nofork")
+	stdout, err := daemon.StdoutPipe()
+	c.NoError(err)
+	c.NoError(daemon.Start())
+
+	t.Cleanup(func() {
+		daemon.Process.Kill()
+		daemon.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	c.NoError(err)
+
+	return strings.TrimSpace(address)
+}
+
+func TestSchemeFromVariant(t *testing.T) {
+	c := require.New(t)
+
+	scheme, err := schemeFromVariant(dbus.MakeVariant(uint32(1)))
+	c.NoError(err)
+	c.Equal(PreferDark, scheme)
+
+	scheme, err = schemeFromVariant(dbus.MakeVariant(dbus.MakeVariant(uint32(2))))
+	c.NoError(err)
+	c.Equal(PreferLight, scheme)
+
+	_, err = schemeFromVariant(dbus.MakeVariant(uint32(7)))
+	c.ErrorIs(err, ErrInvalidColorScheme)
+
+	_, err = schemeFromVariant(dbus.MakeVariant("dark"))
+	c.ErrorIs(err, ErrInvalidColorScheme)
+
+	c.Equal("dark", PreferDark.String())
+	c.Equal("no preference", NoPreference.String())
+	c.EqualValues("light", NoPreference.Variant())
+}
+
+func TestPortal(t *testing.T) {
+	c := require.New(t)
+
+	address := privateBus(t)
+
+	server, err := dbus.Connect(address)
+	c.NoError(err)
+
+	defer server.Close()
+
+	settings := &fakeSettings{scheme: uint32(PreferDark)}
+	c.NoError(server.Export(settings, portalPath, settingsInterface))
+
+	reply, err := server.RequestName(portalDestination, dbus.NameFlagDoNotQueue)
+	c.NoError(err)
+	c.Equal(dbus.RequestNameReplyPrimaryOwner, reply)
+
+	conn, err := dbus.Connect(address)
+	c.NoError(err)
+
+	portal := NewPortal(conn)
+
+	scheme, err := portal.ColorScheme()
+	c.NoError(err)
+	c.Equal(PreferDark, scheme)
+
+	settings.set(PreferLight, true)
+	scheme, err = portal.ColorScheme()
+	c.NoError(err)
+	c.Equal(PreferLight, scheme)
+
+	schemes, err := portal.Watch()
+	c.NoError(err)
+
+	// Other settings are left out
+	c.NoError(server.Emit(portalPath, settingsInterface+"."+settingChanged, "org.gnome.desktop.interface", "font-name", dbus.MakeVariant("Sans 11")))
+	c.NoError(server.Emit(portalPath, settingsInterface+"."+settingChanged, appearanceNamespace, colorSchemeKey, dbus.MakeVariant(uint32(PreferDark))))
+
+	select {
+	case scheme := <-schemes:
+		c.Equal(PreferDark, scheme)
+	case <-time.After(2 * time.Second):
+		c.Fail("no color scheme change received")
+	}
+
+	c.NoError(portal.Close())
+
+	_, ok := <-schemes
+	c.False(ok)
+}
@@ -0,0 +1,82 @@
+// Package companions renders the palette of a theme for the tools running
+// in the terminal, tmux, fzf, delta, Neovim and ls, so they follow it.
+package companions
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"text/template"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/render"
+)
+
+// Tool is a program altie renders the palette for.
+type Tool string
+
+const (
+	Tmux     Tool = "tmux"
+	Fzf      Tool = "fzf"
+	Delta    Tool = "delta"
+	Neovim   Tool = "nvim"
+	LSColors Tool = "ls-colors"
+)
+
+// Tools lists every tool in the order they're rendered.
+var Tools = []Tool{Tmux, Fzf, Delta, Neovim, LSColors}
+
+var ErrUnknownTool = errors.New("unknown companion tool")
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = template.Must(template.New("companions").Funcs(render.Funcs).ParseFS(templateFiles, "templates/*.tmpl"))
+
+// Output is a tool along with the file it's rendered into.
+type Output struct {
+	Tool Tool
+	Path string
+}
+
+// Outputs lists the tools configured in cfg with their path, a leading
+// "~/" standing for homeDir.
+func Outputs(cfg config.Companions, homeDir string) []Output {
+	paths := map[Tool]string{
+		Tmux:     cfg.Tmux,
+		Fzf:      cfg.Fzf,
+		Delta:    cfg.Delta,
+		Neovim:   cfg.Neovim,
+		LSColors: cfg.LSColors,
+	}
+
+	outputs := make([]Output, 0, len(paths))
+	for _, tool := range Tools {
+		if paths[tool] != "" {
+			outputs = append(outputs, Output{Tool: tool, Path: config.ExpandHome(paths[tool], homeDir)})
+		}
+	}
+
+	return outputs
+}
+
+// Write renders data for every output and writes the files. Nothing is
+// written unless every tool rendered.
+func Write(outputs []Output, data render.Data) error {
+	files := make([]render.File, 0, len(outputs))
+	for _, output := range outputs {
+		t := templates.Lookup(string(output.Tool) + ".tmpl")
+		if t == nil {
+			return fmt.Errorf("%w: %q", ErrUnknownTool, output.Tool)
+		}
+
+		files = append(files, render.File{Name: string(output.Tool), Template: t, Path: output.Path})
+	}
+
+	rendered, err := render.Render(files, data)
+	if err != nil {
+		return err
+	}
+
+	return render.Save(rendered)
+}
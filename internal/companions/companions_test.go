@@ -0,0 +1,105 @@
+package companions
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/render"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestWriteGolden(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themePalette, err := themes.LoadPalette(filepath.Join("..", "..", "themes", "Nord.toml"))
+	require.NoError(t, err)
+
+	outputs := make([]Output, 0, len(Tools))
+	for _, tool := range Tools {
+		outputs = append(outputs, Output{Tool: tool, Path: filepath.Join(tmpDir, string(tool))})
+	}
+	require.NoError(t, Write(outputs, render.NewData("Nord", themePalette)))
+
+	for _, output := range outputs {
+		t.Run(string(output.Tool), func(t *testing.T) {
+			c := require.New(t)
+
+			content, err := os.ReadFile(output.Path)
+			c.NoError(err)
+
+			golden := filepath.Join("testdata", "Nord."+string(output.Tool)+".golden")
+			if *update {
+				c.NoError(os.WriteFile(golden, content, 0o644))
+			}
+
+			expected, err := os.ReadFile(golden)
+			c.NoError(err)
+			c.Equal(string(expected), string(content))
+		})
+	}
+
+	err = Write([]Output{{Tool: "vim", Path: filepath.Join(tmpDir, "vim")}}, render.NewData("Nord", themePalette))
+	require.ErrorIs(t, err, ErrUnknownTool)
+	require.NoFileExists(t, filepath.Join(tmpDir, "vim"))
+}
+
+func TestOutputs(t *testing.T) {
+	c := require.New(t)
+
+	c.Empty(Outputs(config.Companions{}, "/home/altie"))
+	c.Equal([]Output{
+		{Tool: Tmux, Path: "/home/altie/.config/tmux/altie.conf"},
+		{Tool: Neovim, Path: "/etc/altie.lua"},
+	}, Outputs(config.Companions{Neovim: "/etc/altie.lua", Tmux: "~/.config/tmux/altie.conf"}, "/home/altie"))
+}
+
+func TestWrite(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themePalette, err := themes.LoadPalette(filepath.Join("..", "..", "themes", "Nord.toml"))
+	c.NoError(err)
+
+	outputs := []Output{
+		{Tool: Fzf, Path: filepath.Join(tmpDir, "fzf", "altie.sh")},
+		{Tool: LSColors, Path: filepath.Join(tmpDir, "ls-colors.sh")},
+	}
+	c.NoError(Write(outputs, render.NewData("Nord", themePalette)))
+
+	content, err := os.ReadFile(outputs[0].Path)
+	c.NoError(err)
+	c.Contains(string(content), "--color=fg:#d8dee9,bg:#2e3440")
+
+	content, err = os.ReadFile(outputs[1].Path)
+	c.NoError(err)
+	c.Contains(string(content), "di=1;38;2;129;161;193")
+
+	// A tool failing to render leaves every file as it was
+	previous := templates
+	defer func() { templates = previous }()
+
+	templates = template.Must(previous.Clone())
+	template.Must(templates.New("ls-colors.tmpl").Funcs(template.FuncMap{
+		"fail": func() (string, error) { return "", errors.New("broken template") },
+	}).Parse("{{fail}}"))
+
+	c.NoError(os.Remove(outputs[0].Path))
+	err = Write(outputs, render.NewData("Nord", themePalette))
+	c.EqualError(err, "rendering ls-colors: template: ls-colors.tmpl:1:2: executing \"ls-colors.tmpl\" at <fail>: error calling fail: broken template")
+	c.NoFileExists(outputs[0].Path)
+}
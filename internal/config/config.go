@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/copydataai/altie/internal/fonts"
 )
 
 const (
-	defaultFont     = "monoscape"
+	defaultFont     = "monospace"
 	defaultFontSize = 14
 )
 
@@ -20,6 +22,10 @@ type AppConfig struct {
 	ConfigDir       string
 	ConfigFilePath  string
 	ThemesDir       string
+	IndexPath       string
+	StatePath       string
+	FontCachePath   string
+	FontDirs        []string
 	AlacrittyDir    string
 	AlacrittyConfig string
 }
@@ -31,6 +37,10 @@ func NewAppConfig(homeDir string) *AppConfig {
 		ConfigDir:       baseDir,
 		ConfigFilePath:  filepath.Join(baseDir, "altie.conf"),
 		ThemesDir:       filepath.Join(baseDir, "themes"),
+		IndexPath:       filepath.Join(baseDir, "index.json"),
+		StatePath:       filepath.Join(baseDir, "state.json"),
+		FontCachePath:   filepath.Join(baseDir, "fonts.json"),
+		FontDirs:        fonts.Dirs(homeDir),
 		AlacrittyDir:    filepath.Join(homeDir, ".config", "alacritty"),
 		AlacrittyConfig: filepath.Join(homeDir, ".config", "alacritty", "alacritty.toml"),
 	}
@@ -46,11 +56,83 @@ type ThemeConfig struct {
 	LastMod  string   `toml:"LastModified"`
 	FontSize int64    `toml:"FontSize"`
 	Font     string   `toml:"Font"`
+	// Faces override the family and style of each style of Font
+	Faces FontFaces `toml:"Faces,omitempty"`
+	// Offset adds to the spacing between characters and lines, GlyphOffset
+	// moves the glyphs inside their cells, both in pixels
+	Offset      FontOffset `toml:"Offset,omitempty"`
+	GlyphOffset FontOffset `toml:"GlyphOffset,omitempty"`
+	// BuiltinBoxDrawing is left to alacritty, which draws them, when unset
+	BuiltinBoxDrawing *bool `toml:"BuiltinBoxDrawing,omitempty"`
+}
+
+// FontFace picks the font of a style, an empty Family is Font and an
+// empty Style is the one alacritty picks for the style.
+type FontFace struct {
+	Family string `toml:"Family,omitempty"`
+	Style  string `toml:"Style,omitempty"`
+}
+
+// FontFaces are the faces of the four styles alacritty draws text with.
+type FontFaces struct {
+	Normal     FontFace `toml:"Normal,omitempty"`
+	Bold       FontFace `toml:"Bold,omitempty"`
+	Italic     FontFace `toml:"Italic,omitempty"`
+	BoldItalic FontFace `toml:"BoldItalic,omitempty"`
+}
+
+// FontOffset is a distance in pixels.
+type FontOffset struct {
+	X int64 `toml:"X"`
+	Y int64 `toml:"Y"`
+}
+
+// Schedule switches between a light and a dark theme, either at fixed
+// times or at sunrise and sunset.
+type Schedule struct {
+	Mode       string  `toml:"Mode"`
+	LightTheme string  `toml:"LightTheme"`
+	DarkTheme  string  `toml:"DarkTheme"`
+	LightAt    string  `toml:"LightAt,omitempty"`
+	DarkAt     string  `toml:"DarkAt,omitempty"`
+	Latitude   float64 `toml:"Latitude,omitempty"`
+	Longitude  float64 `toml:"Longitude,omitempty"`
+}
+
+// Companions are the files the palette of the applied theme is rendered
+// into for the tools running in the terminal, empty ones are left out.
+type Companions struct {
+	Tmux     string `toml:"Tmux,omitempty"`
+	Fzf      string `toml:"Fzf,omitempty"`
+	Delta    string `toml:"Delta,omitempty"`
+	Neovim   string `toml:"Neovim,omitempty"`
+	LSColors string `toml:"LSColors,omitempty"`
+}
+
+// Template is a text/template file rendered into Target with the palette
+// of every applied theme.
+type Template struct {
+	Source string `toml:"Source"`
+	Target string `toml:"Target"`
+}
+
+// Hooks are shell commands run before and after every apply. Timeout
+// bounds each of them, as "5s", and OnFailure is either "warn" or
+// "rollback".
+type Hooks struct {
+	PreApply  []string `toml:"PreApply,omitempty"`
+	PostApply []string `toml:"PostApply,omitempty"`
+	Timeout   string   `toml:"Timeout,omitempty"`
+	OnFailure string   `toml:"OnFailure,omitempty"`
 }
 
 type ConfigThemes struct {
 	Config      `toml:"Config"`
 	ThemeConfig `toml:"ConfigTheme"`
+	Schedule    Schedule   `toml:"Schedule,omitempty"`
+	Companions  Companions `toml:"Companions,omitempty"`
+	Templates   []Template `toml:"Templates,omitempty"`
+	Hooks       Hooks      `toml:"Hooks,omitempty"`
 }
 
 func checkLastModThemes(themesDir string, lastMod time.Time) (bool, error) {
@@ -76,6 +158,18 @@ func (config *ConfigThemes) SetModifiedThemes(appConfig *AppConfig, lastMod time
 	return encodeTomlConfig(configFile, config)
 }
 
+// Save writes the config back to altie.conf.
+func (config *ConfigThemes) Save(configFilePath string) error {
+	configFile, err := os.Create(configFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create config file: %w", err)
+	}
+
+	defer configFile.Close()
+
+	return encodeTomlConfig(configFile, config)
+}
+
 func CreateConfig(appConfig *AppConfig) error {
 	err := os.MkdirAll(appConfig.ConfigDir, os.ModePerm)
 	if err != nil {
@@ -90,10 +184,10 @@ func CreateConfig(appConfig *AppConfig) error {
 	defer configFile.Close()
 
 	defaultConfig := &ConfigThemes{
-		Config{
+		Config: Config{
 			ThemesDirectory: appConfig.ThemesDir,
 		},
-		ThemeConfig{
+		ThemeConfig: ThemeConfig{
 			Themes:   []string{},
 			LastMod:  "",
 			FontSize: defaultFontSize,
@@ -113,6 +207,19 @@ func encodeTomlConfig(configFile *os.File, configTheme *ConfigThemes) error {
 	return nil
 }
 
+// ExpandHome replaces a leading "~/" of path with homeDir.
+func ExpandHome(path, homeDir string) string {
+	if path == "~" {
+		return homeDir
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(homeDir, path[2:])
+	}
+
+	return path
+}
+
 func GetHomeDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
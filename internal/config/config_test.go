@@ -31,10 +31,10 @@ func TestCreateConfig(t *testing.T) {
 
 	// Check if the default config is correct
 	expectedConfig := &ConfigThemes{
-		Config{
+		Config: Config{
 			ThemesDirectory: appConfig.ThemesDir,
 		},
-		ThemeConfig{
+		ThemeConfig: ThemeConfig{
 			Themes:   []string{},
 			LastMod:  "",
 			FontSize: defaultFontSize,
@@ -202,6 +202,15 @@ func TestGetHomeDir(t *testing.T) {
 	c.Error(err)
 }
 
+func TestExpandHome(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal("/home/altie/.config/tmux/altie.conf", ExpandHome("~/.config/tmux/altie.conf", "/home/altie"))
+	c.Equal("/home/altie", ExpandHome("~", "/home/altie"))
+	c.Equal("/etc/altie.conf", ExpandHome("/etc/altie.conf", "/home/altie"))
+	c.Equal("~other/altie.conf", ExpandHome("~other/altie.conf", "/home/altie"))
+}
+
 func TestCheckLastModThemes(t *testing.T) {
 	c := require.New(t)
 
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"time"
+)
+
+// maxHistory is how many applied themes the state remembers.
+const maxHistory = 100
+
+// HistoryEntry is a theme applied by altie.
+type HistoryEntry struct {
+	Theme     string    `json:"theme"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// State keeps what altie learns while it's used, next to altie.conf so the
+// config stays hand-editable. Themes are named like ListThemes names them.
+type State struct {
+	Favorites []string            `json:"favorites,omitempty"`
+	Tags      map[string][]string `json:"tags,omitempty"`
+	History   []HistoryEntry      `json:"history,omitempty"`
+}
+
+// LoadState reads the state saved in statePath, an empty one when it
+// doesn't exist yet.
+func LoadState(statePath string) (*State, error) {
+	state := &State{}
+
+	content, err := os.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (state *State) Save(statePath string) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(statePath, content, 0o644)
+}
+
+// AddHistory records that theme was applied at the given time, forgetting
+// the oldest entries past maxHistory.
+func (state *State) AddHistory(theme string, appliedAt time.Time) {
+	state.History = append(state.History, HistoryEntry{Theme: theme, AppliedAt: appliedAt})
+	if len(state.History) > maxHistory {
+		state.History = state.History[len(state.History)-maxHistory:]
+	}
+}
+
+// Recent lists up to limit distinct themes from the history, the last
+// applied first. A limit of 0 lists all of them.
+func (state *State) Recent(limit int) []string {
+	seen := make(map[string]bool)
+	recent := make([]string, 0)
+	for i := len(state.History) - 1; i >= 0; i-- {
+		if limit > 0 && len(recent) == limit {
+			break
+		}
+
+		theme := state.History[i].Theme
+		if seen[theme] {
+			continue
+		}
+
+		seen[theme] = true
+		recent = append(recent, theme)
+	}
+
+	return recent
+}
+
+// IsFavorite reports whether theme was marked as a favorite.
+func (state *State) IsFavorite(theme string) bool {
+	for _, favorite := range state.Favorites {
+		if favorite == theme {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddFavorite marks theme as a favorite, reporting false when it was one.
+func (state *State) AddFavorite(theme string) bool {
+	if state.IsFavorite(theme) {
+		return false
+	}
+
+	state.Favorites = append(state.Favorites, theme)
+
+	return true
+}
+
+// RemoveFavorite unmarks theme as a favorite, reporting false when it
+// wasn't one.
+func (state *State) RemoveFavorite(theme string) bool {
+	for i, favorite := range state.Favorites {
+		if favorite == theme {
+			state.Favorites = append(state.Favorites[:i], state.Favorites[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToggleFavorite marks theme as a favorite, or unmarks it when it was one,
+// and reports whether it's a favorite now.
+func (state *State) ToggleFavorite(theme string) bool {
+	if state.RemoveFavorite(theme) {
+		return false
+	}
+
+	return state.AddFavorite(theme)
+}
+
+// AddTags tags theme, keeping its tags sorted and without repeats.
+func (state *State) AddTags(theme string, tags ...string) {
+	if state.Tags == nil {
+		state.Tags = make(map[string][]string)
+	}
+
+	themeTags := state.Tags[theme]
+	for _, tag := range tags {
+		if !slices.Contains(themeTags, tag) {
+			themeTags = append(themeTags, tag)
+		}
+	}
+
+	slices.Sort(themeTags)
+	state.Tags[theme] = themeTags
+}
+
+// RemoveTags removes tags from theme, forgetting the theme once it has
+// none left.
+func (state *State) RemoveTags(theme string, tags ...string) {
+	themeTags := slices.DeleteFunc(state.Tags[theme], func(tag string) bool {
+		return slices.Contains(tags, tag)
+	})
+
+	if len(themeTags) == 0 {
+		delete(state.Tags, theme)
+		return
+	}
+
+	state.Tags[theme] = themeTags
+}
+
+// Tagged lists the themes tagged with tag, sorted by name.
+func (state *State) Tagged(tag string) []string {
+	themes := make([]string, 0)
+	for theme, tags := range state.Tags {
+		if slices.Contains(tags, tag) {
+			themes = append(themes, theme)
+		}
+	}
+
+	slices.Sort(themes)
+
+	return themes
+}
+
+// Forget drops every trace of the themes, once their files are gone: the
+// favorites, the tags and the history.
+func (state *State) Forget(themes ...string) {
+	state.Favorites = slices.DeleteFunc(state.Favorites, func(favorite string) bool {
+		return slices.Contains(themes, favorite)
+	})
+
+	for _, theme := range themes {
+		delete(state.Tags, theme)
+	}
+
+	state.History = slices.DeleteFunc(state.History, func(entry HistoryEntry) bool {
+		return slices.Contains(themes, entry.Theme)
+	})
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	statePath := filepath.Join(tmpDir, "state.json")
+
+	state, err := LoadState(statePath)
+	c.NoError(err)
+	c.Empty(state.History)
+	c.Empty(state.Recent(0))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	state.AddHistory("Nord.toml", now)
+	state.AddHistory("Dracula.toml", now.Add(time.Hour))
+	state.AddHistory("Nord.toml", now.Add(2*time.Hour))
+	state.AddHistory("catppuccin/Mocha.toml", now.Add(3*time.Hour))
+	state.Favorites = []string{"Dracula.toml"}
+
+	c.Equal([]string{"catppuccin/Mocha.toml", "Nord.toml", "Dracula.toml"}, state.Recent(0))
+	c.Equal([]string{"catppuccin/Mocha.toml", "Nord.toml"}, state.Recent(2))
+	c.True(state.IsFavorite("Dracula.toml"))
+	c.False(state.IsFavorite("Nord.toml"))
+
+	c.NoError(state.Save(statePath))
+
+	loaded, err := LoadState(statePath)
+	c.NoError(err)
+	c.Equal(state, loaded)
+
+	for i := 0; i < maxHistory+10; i++ {
+		state.AddHistory("Nord.toml", now)
+	}
+	c.Len(state.History, maxHistory)
+
+	c.True(state.ToggleFavorite("Nord.toml"))
+	c.Equal([]string{"Dracula.toml", "Nord.toml"}, state.Favorites)
+	c.False(state.ToggleFavorite("Dracula.toml"))
+	c.Equal([]string{"Nord.toml"}, state.Favorites)
+
+	c.False(state.AddFavorite("Nord.toml"))
+	c.True(state.AddFavorite("Dracula.toml"))
+	c.True(state.RemoveFavorite("Nord.toml"))
+	c.False(state.RemoveFavorite("Nord.toml"))
+	c.Equal([]string{"Dracula.toml"}, state.Favorites)
+
+	state.AddTags("Nord.toml", "work", "presentation")
+	state.AddTags("Nord.toml", "work")
+	state.AddTags("Dracula.toml", "work")
+	c.Equal([]string{"presentation", "work"}, state.Tags["Nord.toml"])
+	c.Equal([]string{"Dracula.toml", "Nord.toml"}, state.Tagged("work"))
+	c.Empty(state.Tagged("night"))
+
+	state.RemoveTags("Nord.toml", "work")
+	c.Equal([]string{"presentation"}, state.Tags["Nord.toml"])
+	state.RemoveTags("Nord.toml", "presentation")
+	c.NotContains(state.Tags, "Nord.toml")
+	c.Equal([]string{"Dracula.toml"}, state.Tagged("work"))
+
+	c.NoError(os.WriteFile(statePath, []byte("{"), 0o644))
+	_, err = LoadState(statePath)
+	c.Error(err)
+}
+
+func TestStateForget(t *testing.T) {
+	c := require.New(t)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := &State{Favorites: []string{"Nord.toml", "Dracula.toml"}}
+	state.AddTags("Nord.toml", "work")
+	state.AddTags("Dracula.toml", "night")
+	state.AddHistory("Nord.toml", now)
+	state.AddHistory("Dracula.toml", now)
+
+	state.Forget("Nord.toml", "Missing.toml")
+	c.Equal([]string{"Dracula.toml"}, state.Favorites)
+	c.Equal(map[string][]string{"Dracula.toml": {"night"}}, state.Tags)
+	c.Equal([]string{"Dracula.toml"}, state.Recent(0))
+}
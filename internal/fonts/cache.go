@@ -0,0 +1,72 @@
+package fonts
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"time"
+)
+
+// cacheVersion is bumped whenever Face gains a field, so older caches are
+// read again instead of missing it.
+const cacheVersion = 1
+
+// cache keeps the faces found in Roots with the modification time of
+// every directory walked, a font added or removed changes the time of its
+// directory like fontconfig relies on.
+type cache struct {
+	Version int                  `json:"version"`
+	Roots   []string             `json:"roots"`
+	Dirs    map[string]time.Time `json:"dirs"`
+	Faces   []Face               `json:"faces"`
+}
+
+// fresh tells whether no directory of the cache changed, appeared or
+// disappeared since it was written.
+func (c *cache) fresh(dirs []string) bool {
+	if c.Version != cacheVersion || !slices.Equal(c.Roots, dirs) {
+		return false
+	}
+
+	for _, dir := range dirs {
+		_, err := os.Stat(dir)
+		_, walked := c.Dirs[dir]
+		if (err == nil) != walked {
+			return false
+		}
+	}
+
+	for dir, modTime := range c.Dirs {
+		info, err := os.Stat(dir)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Load returns the faces of dirs like Discover, reading them from the
+// cache at cachePath unless a directory changed since it was written, in
+// which case the fonts are read again and the cache rewritten.
+func Load(cachePath string, dirs []string) ([]Face, error) {
+	content, err := os.ReadFile(cachePath)
+	if err == nil {
+		previous := &cache{}
+		if json.Unmarshal(content, previous) == nil && previous.fresh(dirs) {
+			return previous.Faces, nil
+		}
+	}
+
+	faces, walked, err := discover(dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err = json.MarshalIndent(cache{cacheVersion, dirs, walked, faces}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return faces, os.WriteFile(cachePath, content, 0o644)
+}
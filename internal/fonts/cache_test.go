@@ -0,0 +1,69 @@
+package fonts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	user := filepath.Join(tmpDir, "fonts")
+	cachePath := filepath.Join(tmpDir, "fonts.json")
+	dirs := []string{user, filepath.Join(tmpDir, "missing")}
+	installFonts(c, user, map[string][]byte{
+		"fira/FiraCode-Regular.ttf": buildFont(testFont{family: "Fira Code", style: "Regular", fixedPitch: true}),
+	})
+
+	faces, err := Load(cachePath, dirs)
+	c.NoError(err)
+	c.Len(faces, 1)
+	c.FileExists(cachePath)
+
+	// The cache answers while the directories are the same, even when a
+	// font changed in place
+	c.NoError(os.WriteFile(filepath.Join(user, "fira", "FiraCode-Regular.ttf"), buildFont(testFont{family: "Changed", style: "Regular"}), 0o644))
+	faces, err = Load(cachePath, dirs)
+	c.NoError(err)
+	c.Equal("Fira Code", faces[0].Family)
+
+	// A font added to a subdirectory is found
+	later := time.Now().Add(time.Minute)
+	installFonts(c, user, map[string][]byte{
+		"fira/FiraCode-Bold.ttf": buildFont(testFont{family: "Fira Code", style: "Bold", fixedPitch: true, bold: true}),
+	})
+	c.NoError(os.Chtimes(filepath.Join(user, "fira"), later, later))
+
+	faces, err = Load(cachePath, dirs)
+	c.NoError(err)
+	c.Len(faces, 2)
+
+	// So is a directory that appeared
+	installFonts(c, dirs[1], map[string][]byte{
+		"Iosevka.ttf": buildFont(testFont{family: "Iosevka", style: "Regular", fixedPitch: true}),
+	})
+
+	faces, err = Load(cachePath, dirs)
+	c.NoError(err)
+	c.Len(faces, 3)
+
+	// Other directories aren't answered from the cache
+	faces, err = Load(cachePath, dirs[1:])
+	c.NoError(err)
+	c.Len(faces, 1)
+
+	// A broken cache is rebuilt
+	c.NoError(os.WriteFile(cachePath, []byte("{"), 0o644))
+	faces, err = Load(cachePath, dirs)
+	c.NoError(err)
+	c.Len(faces, 3)
+}
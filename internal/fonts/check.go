@@ -0,0 +1,160 @@
+package fonts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrUnknownFamily = errors.New("font family not installed")
+
+// genericFamilies are the fontconfig aliases, they always resolve to an
+// installed font.
+var genericFamilies = map[string]bool{
+	"monospace":  true,
+	"mono":       true,
+	"sans-serif": true,
+	"sans":       true,
+	"serif":      true,
+}
+
+// maxSuggestions is how many close names Check offers for a typo.
+const maxSuggestions = 3
+
+// IsGeneric tells whether name is a fontconfig alias rather than a family.
+func IsGeneric(name string) bool {
+	return genericFamilies[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// Find returns the family named name, compared regardless of case like
+// fontconfig does.
+func Find(families []Family, name string) (Family, bool) {
+	for _, family := range families {
+		if strings.EqualFold(family.Name, name) {
+			return family, true
+		}
+	}
+
+	return Family{}, false
+}
+
+// Suggest returns up to limit family names close to name, the closest
+// first. A family is close when it contains name, or the other way
+// around, or when a few edits turn one into the other.
+func Suggest(families []Family, name string, limit int) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	wanted := strings.ToLower(strings.TrimSpace(name))
+	if wanted == "" {
+		return nil
+	}
+
+	maxDistance := max(2, len([]rune(wanted))/3)
+
+	candidates := make([]candidate, 0)
+	for _, family := range families {
+		other := strings.ToLower(family.Name)
+
+		distance := levenshtein(wanted, other)
+		if strings.Contains(other, wanted) || strings.Contains(wanted, other) {
+			// Closer than any typo so "Fira" suggests "Fira Code" first
+			distance = 0
+		}
+
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{family.Name, distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	names := make([]string, 0, limit)
+	for _, candidate := range candidates {
+		if len(names) == limit {
+			break
+		}
+
+		names = append(names, candidate.name)
+	}
+
+	return names
+}
+
+// levenshtein counts the insertions, deletions and substitutions turning
+// a into b.
+func levenshtein(a, b string) int {
+	first, second := []rune(a), []rune(b)
+
+	previous := make([]int, len(second)+1)
+	current := make([]int, len(second)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(first); i++ {
+		current[0] = i
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(second)]
+}
+
+// MissingStyles lists the styles alacritty asks for that the family has
+// no face for, among "bold", "italic" and "bold italic". A variable face
+// covers the bold of its slant.
+func (f Family) MissingStyles() []string {
+	var bold, italic, boldItalic bool
+	for _, face := range f.Faces {
+		switch {
+		case face.Italic:
+			italic = italic || !face.Bold
+			boldItalic = boldItalic || face.Bold || face.Variable
+		default:
+			bold = bold || face.Bold || face.Variable
+		}
+	}
+
+	missing := make([]string, 0)
+	if !bold {
+		missing = append(missing, "bold")
+	}
+	if !italic {
+		missing = append(missing, "italic")
+	}
+	if !boldItalic {
+		missing = append(missing, "bold italic")
+	}
+
+	return missing
+}
+
+// Check returns the family named name, or ErrUnknownFamily with the close
+// names when it isn't installed.
+func Check(families []Family, name string) (Family, error) {
+	family, ok := Find(families, name)
+	if ok {
+		return family, nil
+	}
+
+	suggestions := Suggest(families, name, maxSuggestions)
+	if len(suggestions) > 0 {
+		return Family{}, fmt.Errorf("%w: %s, did you mean %s?", ErrUnknownFamily, name, strings.Join(suggestions, ", "))
+	}
+
+	return Family{}, fmt.Errorf("%w: %s", ErrUnknownFamily, name)
+}
@@ -0,0 +1,73 @@
+package fonts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testFamilies = Families([]Face{
+	{Family: "DejaVu Sans Mono", Style: "Book", Monospace: true},
+	{Family: "DejaVu Sans Mono", Style: "Bold", Monospace: true, Bold: true},
+	{Family: "Fira Code", Style: "Regular", Monospace: true},
+	{Family: "Fira Code", Style: "Bold", Monospace: true, Bold: true},
+	{Family: "Fira Mono", Style: "Regular", Monospace: true},
+	{Family: "JetBrains Mono", Style: "Regular", Monospace: true, Variable: true},
+	{Family: "JetBrains Mono", Style: "Italic", Monospace: true, Italic: true, Variable: true},
+	{Family: "Iosevka", Style: "Regular", Monospace: true},
+	{Family: "Iosevka", Style: "Bold", Monospace: true, Bold: true},
+	{Family: "Iosevka", Style: "Italic", Monospace: true, Italic: true},
+	{Family: "Iosevka", Style: "Bold Italic", Monospace: true, Bold: true, Italic: true},
+})
+
+func TestIsGeneric(t *testing.T) {
+	c := require.New(t)
+
+	c.True(IsGeneric("monospace"))
+	c.True(IsGeneric("Monospace"))
+	c.False(IsGeneric("Fira Code"))
+}
+
+func TestSuggest(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal([]string{"Fira Code"}, Suggest(testFamilies, "Fira Cdoe", 3))
+	c.Equal([]string{"JetBrains Mono"}, Suggest(testFamilies, "jetbrains", 3))
+	c.Equal([]string{"Fira Code", "Fira Mono"}, Suggest(testFamilies, "Fira", 3))
+	c.Equal([]string{"Fira Code"}, Suggest(testFamilies, "Fira", 1))
+	c.Empty(Suggest(testFamilies, "Comic Sans", 3))
+	c.Empty(Suggest(testFamilies, "", 3))
+}
+
+func TestMissingStyles(t *testing.T) {
+	c := require.New(t)
+
+	missing := func(name string) []string {
+		family, ok := Find(testFamilies, name)
+		c.True(ok)
+
+		return family.MissingStyles()
+	}
+
+	c.Empty(missing("Iosevka"))
+	c.Equal([]string{"italic", "bold italic"}, missing("Fira Code"))
+	c.Equal([]string{"bold", "italic", "bold italic"}, missing("Fira Mono"))
+	// The weight axis of a variable font gives its bold
+	c.Empty(missing("JetBrains Mono"))
+}
+
+func TestCheck(t *testing.T) {
+	c := require.New(t)
+
+	family, err := Check(testFamilies, "fira code")
+	c.NoError(err)
+	c.Equal("Fira Code", family.Name)
+
+	_, err = Check(testFamilies, "Fira Cdoe")
+	c.ErrorIs(err, ErrUnknownFamily)
+	c.ErrorContains(err, "did you mean Fira Code?")
+
+	_, err = Check(testFamilies, "Comic Sans")
+	c.ErrorIs(err, ErrUnknownFamily)
+	c.NotContains(err.Error(), "did you mean")
+}
@@ -0,0 +1,163 @@
+package fonts
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fontExtensions are the files read as fonts, ".ttc" and ".otc" are
+// collections of several fonts.
+var fontExtensions = map[string]bool{
+	".ttf": true,
+	".otf": true,
+	".ttc": true,
+	".otc": true,
+}
+
+// Family gathers the faces sharing a family name, the name alacritty
+// takes in font.normal.family.
+type Family struct {
+	Name   string
+	Styles []string
+	Faces  []Face
+	// Monospace is set when every face of the family is
+	Monospace bool
+}
+
+// Dirs are where fontconfig finds fonts by default, the ones of the user
+// first.
+func Dirs(homeDir string) []string {
+	dirs := []string{
+		filepath.Join(homeDir, ".local", "share", "fonts"),
+		filepath.Join(homeDir, ".fonts"),
+		"/usr/local/share/fonts",
+		"/usr/share/fonts",
+	}
+
+	if runtime.GOOS == "darwin" {
+		dirs = append(dirs, filepath.Join(homeDir, "Library", "Fonts"), "/Library/Fonts", "/System/Library/Fonts")
+	}
+
+	return dirs
+}
+
+// Discover reads the faces of every font file in dirs and their
+// subdirectories. Missing directories, unreadable files and files that
+// aren't fonts are skipped like fontconfig does.
+func Discover(dirs []string) ([]Face, error) {
+	faces, _, err := discover(dirs)
+
+	return faces, err
+}
+
+// discover is Discover, also returning the modification time of every
+// directory it walked so a cache can tell when they change.
+func discover(dirs []string) ([]Face, map[string]time.Time, error) {
+	faces := make([]Face, 0)
+	walked := make(map[string]time.Time)
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || seen[path] {
+				return nil
+			}
+
+			if d.IsDir() {
+				info, err := d.Info()
+				if err == nil {
+					walked[path] = info.ModTime()
+				}
+
+				return nil
+			}
+
+			if !fontExtensions[strings.ToLower(filepath.Ext(path))] {
+				return nil
+			}
+
+			seen[path] = true
+
+			found, err := readFile(path)
+			if err != nil {
+				return nil
+			}
+
+			faces = append(faces, found...)
+
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return faces, walked, nil
+}
+
+func readFile(path string) ([]Face, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ReadFaces(file, path)
+}
+
+// Families groups faces by family, sorted by name regardless of case.
+func Families(faces []Face) []Family {
+	byName := make(map[string]*Family)
+	for _, face := range faces {
+		family, ok := byName[face.Family]
+		if !ok {
+			family = &Family{Name: face.Family, Monospace: true}
+			byName[face.Family] = family
+		}
+
+		family.Faces = append(family.Faces, face)
+		family.Monospace = family.Monospace && face.Monospace
+	}
+
+	families := make([]Family, 0, len(byName))
+	for _, family := range byName {
+		styles := make(map[string]bool, len(family.Faces))
+		for _, face := range family.Faces {
+			if face.Style != "" && !styles[face.Style] {
+				styles[face.Style] = true
+				family.Styles = append(family.Styles, face.Style)
+			}
+		}
+
+		sort.Strings(family.Styles)
+		families = append(families, *family)
+	}
+
+	sort.Slice(families, func(i, j int) bool {
+		first, second := strings.ToLower(families[i].Name), strings.ToLower(families[j].Name)
+		if first != second {
+			return first < second
+		}
+
+		return families[i].Name < families[j].Name
+	})
+
+	return families
+}
+
+// Monospace keeps the monospace families, the ones a terminal can use.
+func Monospace(families []Family) []Family {
+	monospace := make([]Family, 0, len(families))
+	for _, family := range families {
+		if family.Monospace {
+			monospace = append(monospace, family)
+		}
+	}
+
+	return monospace
+}
@@ -0,0 +1,71 @@
+package fonts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// installFonts writes the fonts into dir, named by their file.
+func installFonts(c *require.Assertions, dir string, fonts map[string][]byte) {
+	for name, content := range fonts {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		c.NoError(os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		c.NoError(os.WriteFile(path, content, 0o644))
+	}
+}
+
+func TestDirs(t *testing.T) {
+	c := require.New(t)
+
+	dirs := Dirs("/home/altie")
+	c.Equal("/home/altie/.local/share/fonts", dirs[0])
+	c.Contains(dirs, "/usr/share/fonts")
+}
+
+func TestDiscover(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	user := filepath.Join(tmpDir, "user")
+	system := filepath.Join(tmpDir, "system")
+	installFonts(c, user, map[string][]byte{
+		"FiraCode-Regular.ttf": buildFont(testFont{family: "Fira Code", style: "Regular", fixedPitch: true}),
+		"FiraCode-Bold.TTF":    buildFont(testFont{family: "Fira Code", style: "Bold", fixedPitch: true, bold: true}),
+		"broken.ttf":           []byte("not a font"),
+		"README.md":            []byte("# Fonts\n"),
+	})
+	installFonts(c, system, map[string][]byte{
+		"truetype/dejavu/DejaVuSans.ttf":     buildFont(testFont{family: "DejaVu Sans", style: "Book"}),
+		"truetype/dejavu/DejaVuSansMono.ttf": buildFont(testFont{family: "DejaVu Sans Mono", style: "Book", fixedPitch: true}),
+		"opentype/iosevka/Iosevka.ttc": buildCollection(
+			testFont{family: "Iosevka", style: "Regular", fixedPitch: true},
+			testFont{family: "Iosevka", style: "Italic", fixedPitch: true, italic: true},
+		),
+	})
+
+	// Missing directories and the same directory twice are fine
+	faces, err := Discover([]string{user, filepath.Join(tmpDir, "missing"), system, system})
+	c.NoError(err)
+	c.Len(faces, 6)
+
+	families := Families(faces)
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.Name)
+	}
+	c.Equal([]string{"DejaVu Sans", "DejaVu Sans Mono", "Fira Code", "Iosevka"}, names)
+	c.Equal([]string{"Bold", "Regular"}, families[2].Styles)
+	c.Equal([]string{"Italic", "Regular"}, families[3].Styles)
+	c.False(families[0].Monospace)
+
+	monospace := Monospace(families)
+	c.Len(monospace, 3)
+	c.Equal("DejaVu Sans Mono", monospace[0].Name)
+}
@@ -0,0 +1,313 @@
+// Package fonts finds the fonts installed on the system by reading the
+// tables of their TrueType and OpenType files, without fontconfig.
+package fonts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+)
+
+var (
+	ErrNotFont     = errors.New("not a TrueType or OpenType font")
+	ErrInvalidFont = errors.New("invalid font")
+)
+
+// Name IDs of the name table
+const (
+	nameFamily               = 1
+	nameSubfamily            = 2
+	nameTypographicFamily    = 16
+	nameTypographicSubfamily = 17
+)
+
+// Limits against corrupted files announcing absurd counts
+const (
+	maxTables     = 256
+	maxCollection = 256
+	maxNames      = 4096
+)
+
+// PANOSE classification of the OS/2 table, a latin text font with a
+// monospaced proportion
+const (
+	panoseLatinText  = 2
+	panoseMonospaced = 9
+)
+
+// Face is a font of a file, a collection holds several.
+type Face struct {
+	Family    string
+	Style     string
+	Path      string
+	Monospace bool
+	Bold      bool
+	Italic    bool
+	// Variable fonts draw every weight of their axes from a single face
+	Variable bool
+	// Weight goes from 100, thin, to 900, black, 400 is regular
+	Weight int
+}
+
+// table locates a table in the font file.
+type table struct {
+	offset int64
+	length int64
+}
+
+// ReadFaces reads the faces of a font file, a TrueType or OpenType font
+// or a collection of them. path is only recorded in the faces.
+func ReadFaces(r io.ReaderAt, path string) ([]Face, error) {
+	header := make([]byte, 12)
+	_, err := r.ReadAt(header, 0)
+	if err != nil {
+		return nil, ErrNotFont
+	}
+
+	switch string(header[:4]) {
+	case "\x00\x01\x00\x00", "OTTO", "true":
+		face, err := readFace(r, 0, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return []Face{face}, nil
+	case "ttcf":
+		count := binary.BigEndian.Uint32(header[8:])
+		if count == 0 || count > maxCollection {
+			return nil, fmt.Errorf("%w: %d fonts in the collection", ErrInvalidFont, count)
+		}
+
+		offsets := make([]byte, 4*count)
+		_, err = r.ReadAt(offsets, 12)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFont, err)
+		}
+
+		faces := make([]Face, 0, count)
+		for i := uint32(0); i < count; i++ {
+			face, err := readFace(r, int64(binary.BigEndian.Uint32(offsets[4*i:])), path)
+			if err != nil {
+				return nil, err
+			}
+
+			faces = append(faces, face)
+		}
+
+		return faces, nil
+	}
+
+	return nil, ErrNotFont
+}
+
+func readFace(r io.ReaderAt, offset int64, path string) (Face, error) {
+	tables, err := readTables(r, offset)
+	if err != nil {
+		return Face{}, err
+	}
+
+	nameTable, ok := tables["name"]
+	if !ok {
+		return Face{}, fmt.Errorf("%w: no name table", ErrInvalidFont)
+	}
+
+	names, err := readNames(r, nameTable)
+	if err != nil {
+		return Face{}, err
+	}
+
+	face := Face{
+		Family: firstName(names, nameTypographicFamily, nameFamily),
+		Style:  firstName(names, nameTypographicSubfamily, nameSubfamily),
+		Path:   path,
+		Weight: 400,
+	}
+
+	if face.Family == "" {
+		return Face{}, fmt.Errorf("%w: no family name", ErrInvalidFont)
+	}
+
+	_, face.Variable = tables["fvar"]
+
+	if post, ok := tables["post"]; ok && post.length >= 16 {
+		fixedPitch, err := readAt(r, post.offset+12, 4)
+		if err != nil {
+			return Face{}, err
+		}
+
+		face.Monospace = binary.BigEndian.Uint32(fixedPitch) != 0
+	}
+
+	if os2, ok := tables["OS/2"]; ok && os2.length >= 64 {
+		content, err := readAt(r, os2.offset, 64)
+		if err != nil {
+			return Face{}, err
+		}
+
+		face.Weight = int(binary.BigEndian.Uint16(content[4:]))
+		face.Monospace = face.Monospace || (content[32] == panoseLatinText && content[35] == panoseMonospaced)
+
+		selection := binary.BigEndian.Uint16(content[62:])
+		face.Italic = selection&1 != 0
+		face.Bold = selection&(1<<5) != 0
+
+		return face, nil
+	}
+
+	// Without an OS/2 table the style comes from the head table
+	if head, ok := tables["head"]; ok && head.length >= 46 {
+		macStyle, err := readAt(r, head.offset+44, 2)
+		if err != nil {
+			return Face{}, err
+		}
+
+		face.Bold = macStyle[1]&1 != 0
+		face.Italic = macStyle[1]&2 != 0
+		if face.Bold {
+			face.Weight = 700
+		}
+	}
+
+	return face, nil
+}
+
+func readAt(r io.ReaderAt, offset int64, length int64) ([]byte, error) {
+	content := make([]byte, length)
+	_, err := r.ReadAt(content, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFont, err)
+	}
+
+	return content, nil
+}
+
+// readTables reads the table directory of the font starting at offset.
+func readTables(r io.ReaderAt, offset int64) (map[string]table, error) {
+	header, err := readAt(r, offset, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int64(binary.BigEndian.Uint16(header[4:]))
+	if count > maxTables {
+		return nil, fmt.Errorf("%w: %d tables", ErrInvalidFont, count)
+	}
+
+	records, err := readAt(r, offset+12, 16*count)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]table, count)
+	for i := int64(0); i < count; i++ {
+		record := records[16*i:]
+		tables[string(record[:4])] = table{
+			offset: int64(binary.BigEndian.Uint32(record[8:])),
+			length: int64(binary.BigEndian.Uint32(record[12:])),
+		}
+	}
+
+	return tables, nil
+}
+
+// readNames reads the family and subfamily names of the name table, in
+// the English name when the font has several languages.
+func readNames(r io.ReaderAt, nameTable table) (map[int]string, error) {
+	header, err := readAt(r, nameTable.offset, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int64(binary.BigEndian.Uint16(header[2:]))
+	storage := nameTable.offset + int64(binary.BigEndian.Uint16(header[4:]))
+	if count > maxNames {
+		return nil, fmt.Errorf("%w: %d names", ErrInvalidFont, count)
+	}
+
+	records, err := readAt(r, nameTable.offset+6, 12*count)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string)
+	scores := make(map[int]int)
+	for i := int64(0); i < count; i++ {
+		record := records[12*i:]
+		platform := binary.BigEndian.Uint16(record[0:])
+		encoding := binary.BigEndian.Uint16(record[2:])
+		language := binary.BigEndian.Uint16(record[4:])
+		id := int(binary.BigEndian.Uint16(record[6:]))
+		length := int64(binary.BigEndian.Uint16(record[8:]))
+		offset := int64(binary.BigEndian.Uint16(record[10:]))
+
+		switch id {
+		case nameFamily, nameSubfamily, nameTypographicFamily, nameTypographicSubfamily:
+		default:
+			continue
+		}
+
+		score := nameScore(platform, encoding, language)
+		if score <= scores[id] {
+			continue
+		}
+
+		content, err := readAt(r, storage+offset, length)
+		if err != nil {
+			return nil, err
+		}
+
+		names[id] = decodeName(platform, content)
+		scores[id] = score
+	}
+
+	return names, nil
+}
+
+// nameScore ranks the records of a name, the English Windows one first,
+// zero for the encodings altie can't read.
+func nameScore(platform, encoding, language uint16) int {
+	switch {
+	case platform == 3 && (encoding == 1 || encoding == 10) && language == 0x0409:
+		return 4
+	case platform == 3 && (encoding == 1 || encoding == 10):
+		return 1
+	case platform == 1 && encoding == 0 && language == 0:
+		return 3
+	case platform == 0:
+		return 2
+	}
+
+	return 0
+}
+
+// decodeName reads UTF-16BE names, Macintosh ones are read as Latin-1
+// which matches Mac Roman for ASCII names.
+func decodeName(platform uint16, content []byte) string {
+	if platform == 1 {
+		runes := make([]rune, len(content))
+		for i, b := range content {
+			runes[i] = rune(b)
+		}
+
+		return string(runes)
+	}
+
+	units := make([]uint16, len(content)/2)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(content[2*i:])
+	}
+
+	return string(utf16.Decode(units))
+}
+
+func firstName(names map[int]string, ids ...int) string {
+	for _, id := range ids {
+		if names[id] != "" {
+			return names[id]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,228 @@
+package fonts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testFont describes the tables of a font built by buildFont.
+type testFont struct {
+	family, style         string
+	typographic           string
+	fixedPitch            bool
+	panoseProportion      byte
+	weight                uint16
+	bold, italic          bool
+	withoutOS2, macintosh bool
+	otherLanguage         string
+}
+
+// nameRecord is a record of the name table with its UTF-16 or Latin-1 text.
+type nameRecord struct {
+	platform, encoding, language, id uint16
+	text                             string
+}
+
+func encodeName(record nameRecord) []byte {
+	if record.platform == 1 {
+		return []byte(record.text)
+	}
+
+	var out bytes.Buffer
+	for _, unit := range utf16.Encode([]rune(record.text)) {
+		binary.Write(&out, binary.BigEndian, unit)
+	}
+
+	return out.Bytes()
+}
+
+func nameTable(records []nameRecord) []byte {
+	var header, storage bytes.Buffer
+	binary.Write(&header, binary.BigEndian, []uint16{0, uint16(len(records)), uint16(6 + 12*len(records))})
+	for _, record := range records {
+		text := encodeName(record)
+		binary.Write(&header, binary.BigEndian, []uint16{record.platform, record.encoding, record.language, record.id, uint16(len(text)), uint16(storage.Len())})
+		storage.Write(text)
+	}
+
+	return append(header.Bytes(), storage.Bytes()...)
+}
+
+func (font testFont) tables() map[string][]byte {
+	platform, encoding, language := uint16(3), uint16(1), uint16(0x0409)
+	if font.macintosh {
+		platform, encoding, language = 1, 0, 0
+	}
+
+	records := []nameRecord{
+		{platform, encoding, language, nameFamily, font.family},
+		{platform, encoding, language, nameSubfamily, font.style},
+	}
+
+	if font.typographic != "" {
+		records = append(records, nameRecord{platform, encoding, language, nameTypographicFamily, font.typographic})
+	}
+
+	// A translated family in front of the English one
+	if font.otherLanguage != "" {
+		records = append([]nameRecord{{3, 1, 0x0411, nameFamily, font.otherLanguage}}, records...)
+	}
+
+	post := make([]byte, 32)
+	if font.fixedPitch {
+		binary.BigEndian.PutUint32(post[12:], 1)
+	}
+
+	var style uint16
+	if font.bold {
+		style |= 1
+	}
+	if font.italic {
+		style |= 2
+	}
+
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[44:], style)
+
+	tables := map[string][]byte{"name": nameTable(records), "post": post, "head": head}
+	if !font.withoutOS2 {
+		os2 := make([]byte, 96)
+		binary.BigEndian.PutUint16(os2[4:], font.weight)
+		os2[32] = panoseLatinText
+		os2[35] = font.panoseProportion
+
+		var selection uint16
+		if font.italic {
+			selection |= 1
+		}
+		if font.bold {
+			selection |= 1 << 5
+		}
+		binary.BigEndian.PutUint16(os2[62:], selection)
+
+		tables["OS/2"] = os2
+	}
+
+	return tables
+}
+
+// writeSFNT lays out the table directory at offset and the tables after it.
+func writeSFNT(out *bytes.Buffer, offset int, tables map[string][]byte) {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	directory := 12 + 16*len(tags)
+	binary.Write(out, binary.BigEndian, []uint32{0x00010000})
+	binary.Write(out, binary.BigEndian, []uint16{uint16(len(tags)), 0, 0, 0})
+
+	position := offset + directory
+	for _, tag := range tags {
+		out.WriteString(tag)
+		binary.Write(out, binary.BigEndian, []uint32{0, uint32(position), uint32(len(tables[tag]))})
+		position += len(tables[tag])
+	}
+
+	for _, tag := range tags {
+		out.Write(tables[tag])
+	}
+}
+
+func buildFont(font testFont) []byte {
+	var out bytes.Buffer
+	writeSFNT(&out, 0, font.tables())
+
+	return out.Bytes()
+}
+
+// buildCollection writes fonts one after the other behind a ttcf header.
+func buildCollection(fonts ...testFont) []byte {
+	header := 12 + 4*len(fonts)
+
+	bodies := make([][]byte, 0, len(fonts))
+	offset := header
+	offsets := make([]uint32, 0, len(fonts))
+	for _, font := range fonts {
+		var body bytes.Buffer
+		writeSFNT(&body, offset, font.tables())
+		bodies = append(bodies, body.Bytes())
+		offsets = append(offsets, uint32(offset))
+		offset += body.Len()
+	}
+
+	var out bytes.Buffer
+	out.WriteString("ttcf")
+	binary.Write(&out, binary.BigEndian, []uint32{0x00010000, uint32(len(fonts))})
+	binary.Write(&out, binary.BigEndian, offsets)
+	for _, body := range bodies {
+		out.Write(body)
+	}
+
+	return out.Bytes()
+}
+
+func TestReadFaces(t *testing.T) {
+	c := require.New(t)
+
+	faces, err := ReadFaces(bytes.NewReader(buildFont(testFont{
+		family:        "Fira Code",
+		style:         "Bold",
+		fixedPitch:    true,
+		weight:        700,
+		bold:          true,
+		otherLanguage: "ファイラ",
+	})), "FiraCode-Bold.ttf")
+	c.NoError(err)
+	c.Equal([]Face{{Family: "Fira Code", Style: "Bold", Path: "FiraCode-Bold.ttf", Monospace: true, Bold: true, Weight: 700}}, faces)
+
+	// The typographic family groups the weights a legacy family splits
+	faces, err = ReadFaces(bytes.NewReader(buildFont(testFont{
+		family:           "JetBrains Mono ExtraBold",
+		style:            "Italic",
+		typographic:      "JetBrains Mono",
+		panoseProportion: panoseMonospaced,
+		weight:           800,
+		italic:           true,
+	})), "")
+	c.NoError(err)
+	c.Equal("JetBrains Mono", faces[0].Family)
+	c.True(faces[0].Monospace)
+	c.True(faces[0].Italic)
+	c.Equal(800, faces[0].Weight)
+
+	// Old Macintosh fonts have their names and styles elsewhere
+	faces, err = ReadFaces(bytes.NewReader(buildFont(testFont{family: "Monaco", style: "Bold Italic", macintosh: true, withoutOS2: true, bold: true, italic: true})), "")
+	c.NoError(err)
+	c.Equal([]Face{{Family: "Monaco", Style: "Bold Italic", Bold: true, Italic: true, Weight: 700}}, faces)
+
+	faces, err = ReadFaces(bytes.NewReader(buildCollection(
+		testFont{family: "Iosevka", style: "Regular", fixedPitch: true, weight: 400},
+		testFont{family: "Iosevka Aile", style: "Regular", weight: 400},
+	)), "Iosevka.ttc")
+	c.NoError(err)
+	c.Len(faces, 2)
+	c.Equal("Iosevka", faces[0].Family)
+	c.True(faces[0].Monospace)
+	c.Equal("Iosevka Aile", faces[1].Family)
+	c.False(faces[1].Monospace)
+
+	_, err = ReadFaces(bytes.NewReader([]byte("wOFF and more bytes")), "")
+	c.ErrorIs(err, ErrNotFont)
+
+	_, err = ReadFaces(bytes.NewReader([]byte("abc")), "")
+	c.ErrorIs(err, ErrNotFont)
+
+	truncated := buildFont(testFont{family: "Fira Code", style: "Regular"})
+	_, err = ReadFaces(bytes.NewReader(truncated[:60]), "")
+	c.ErrorIs(err, ErrInvalidFont)
+
+	_, err = ReadFaces(bytes.NewReader(buildFont(testFont{style: "Regular"})), "")
+	c.ErrorIs(err, ErrInvalidFont)
+}
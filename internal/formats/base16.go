@@ -0,0 +1,89 @@
+package formats
+
+import (
+	"fmt"
+
+	"github.com/copydataai/altie/internal/palette"
+	"gopkg.in/yaml.v3"
+)
+
+// base16Scheme is a base16 or base24 scheme, in the original layout with
+// the colors next to the name or in the tinted-theming one with a palette.
+type base16Scheme struct {
+	System  string            `yaml:"system"`
+	Palette map[string]string `yaml:"palette"`
+	Colors  map[string]any    `yaml:",inline"`
+}
+
+// Slots of the ANSI colors in the base16 and base24 terminal mappings,
+// black to white
+var (
+	base16Normal = [8]string{"base00", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base05"}
+	base16Bright = [8]string{"base03", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base07"}
+	base24Normal = [8]string{"base01", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base06"}
+	base24Bright = [8]string{"base02", "base12", "base14", "base13", "base16", "base17", "base15", "base07"}
+)
+
+func importBase16(content []byte) (*palette.Palette, error) {
+	return importBase(content, false)
+}
+
+func importBase24(content []byte) (*palette.Palette, error) {
+	return importBase(content, true)
+}
+
+// importBase reads a base16 scheme, or a base24 one when base24 is set or
+// the scheme says so or has the base24 colors.
+func importBase(content []byte, base24 bool) (*palette.Palette, error) {
+	scheme := base16Scheme{}
+	err := yaml.Unmarshal(content, &scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := scheme.Palette
+	if colors == nil {
+		colors = make(map[string]string)
+		for key, value := range scheme.Colors {
+			if hex, ok := value.(string); ok {
+				colors[key] = hex
+			}
+		}
+	}
+
+	_, bright := colors["base12"]
+	base24 = base24 || scheme.System == "base24" || (scheme.System == "" && bright)
+
+	p := newPalette()
+	slots := []colorSlot{
+		{"base00", &p.Background},
+		{"base05", &p.Foreground},
+		{"base05", &p.Cursor},
+		{"base00", &p.CursorText},
+		{"base02", &p.SelectionBackground},
+		{"base05", &p.SelectionText},
+	}
+
+	normal, brights := base16Normal, base16Bright
+	if base24 {
+		normal, brights = base24Normal, base24Bright
+	}
+
+	for i := range p.Normal {
+		slots = append(slots, colorSlot{normal[i], &p.Normal[i]}, colorSlot{brights[i], &p.Bright[i]})
+	}
+
+	for _, slot := range slots {
+		value, ok := colors[slot.key]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrMissingColor, slot.key)
+		}
+
+		*slot.color, err = palette.ParseHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slot.key, err)
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,72 @@
+package formats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportBase16(t *testing.T) {
+	c := require.New(t)
+
+	content, err := os.ReadFile(filepath.Join("testdata", "nord.base16.yaml"))
+	c.NoError(err)
+
+	nord, err := Import(Base16, content)
+	c.NoError(err)
+	c.Equal("#2e3440", nord.Background.Hex())
+	c.Equal("#e5e9f0", nord.Foreground.Hex())
+	c.Equal("#2e3440", nord.Normal[0].Hex())
+	c.Equal("#bf616a", nord.Normal[1].Hex())
+	c.Equal("#a3be8c", nord.Normal[2].Hex())
+	c.Equal("#e5e9f0", nord.Normal[7].Hex())
+	c.Equal("#4c566a", nord.Bright[0].Hex())
+	c.Equal(nord.Normal[1], nord.Bright[1])
+	c.Equal("#8fbcbb", nord.Bright[7].Hex())
+	c.Equal("#434c5e", nord.SelectionBackground.Hex())
+	c.Equal(nord.Foreground, nord.Cursor)
+	c.Equal(palette.DefaultFocusedBackground, nord.FocusedBackground)
+
+	_, err = Import(Base24, content)
+	c.ErrorIs(err, ErrMissingColor)
+	c.ErrorContains(err, "base12")
+
+	_, err = Import(Base16, []byte(strings.Replace(string(content), "base0B", "base0b", 1)))
+	c.ErrorIs(err, ErrMissingColor)
+	c.ErrorContains(err, "base0B")
+
+	_, err = Import(Base16, []byte(strings.Replace(string(content), "BF616A", "red", 1)))
+	c.ErrorIs(err, palette.ErrInvalidColor)
+}
+
+func TestImportBase24(t *testing.T) {
+	c := require.New(t)
+
+	content, err := os.ReadFile(filepath.Join("testdata", "dracula.base24.yaml"))
+	c.NoError(err)
+
+	// Base24 schemes are told apart when read as base16 ones
+	for _, format := range []Format{Base16, Base24} {
+		dracula, err := Import(format, content)
+		c.NoError(err)
+		c.Equal("#282a36", dracula.Background.Hex())
+		c.Equal("#363447", dracula.Normal[0].Hex())
+		c.Equal("#f0f1f4", dracula.Normal[7].Hex())
+		c.Equal("#44475a", dracula.Bright[0].Hex())
+		c.Equal("#f28c8c", dracula.Bright[1].Hex())
+		c.Equal("#5af78e", dracula.Bright[2].Hex())
+		c.Equal("#eef5a3", dracula.Bright[3].Hex())
+		c.Equal("#a4ffff", dracula.Bright[4].Hex())
+		c.Equal("#ff92df", dracula.Bright[5].Hex())
+		c.Equal("#97e1f1", dracula.Bright[6].Hex())
+		c.Equal("#ffffff", dracula.Bright[7].Hex())
+	}
+
+	format, err := DetectFormat("dracula.yml")
+	c.NoError(err)
+	c.Equal(Base16, format)
+}
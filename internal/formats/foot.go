@@ -0,0 +1,35 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// exportFoot writes the sections of foot.ini setting the colors, which
+// foot writes without "#".
+func exportFoot(w io.Writer, name string, p *palette.Palette) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "# %s, exported by altie\n\n", name)
+	fmt.Fprintln(out, "[cursor]")
+	fmt.Fprintf(out, "color=%s %s\n\n", bare(p.CursorText), bare(p.Cursor))
+
+	fmt.Fprintln(out, "[colors]")
+	fmt.Fprintf(out, "foreground=%s\n", bare(p.Foreground))
+	fmt.Fprintf(out, "background=%s\n", bare(p.Background))
+	fmt.Fprintf(out, "selection-foreground=%s\n", bare(p.SelectionText))
+	fmt.Fprintf(out, "selection-background=%s\n", bare(p.SelectionBackground))
+
+	for i, color := range p.Normal {
+		fmt.Fprintf(out, "regular%d=%s\n", i, bare(color))
+	}
+
+	for i, color := range p.Bright {
+		fmt.Fprintf(out, "bright%d=%s\n", i, bare(color))
+	}
+
+	return out.Flush()
+}
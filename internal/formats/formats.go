@@ -0,0 +1,281 @@
+// Package formats converts palettes to and from the theme formats of
+// other terminal emulators.
+package formats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// Format is the theme format of a terminal emulator.
+type Format string
+
+const (
+	Kitty           Format = "kitty"
+	WezTerm         Format = "wezterm"
+	Foot            Format = "foot"
+	Ghostty         Format = "ghostty"
+	Xresources      Format = "xresources"
+	WindowsTerminal Format = "windows-terminal"
+	ITerm2          Format = "iterm2"
+	Base16          Format = "base16"
+	Base24          Format = "base24"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format")
+	ErrMissingColor  = errors.New("theme is missing a color")
+)
+
+// exporter writes the palette of the theme name in a format.
+type exporter func(w io.Writer, name string, p *palette.Palette) error
+
+var exporters = map[Format]exporter{
+	Kitty:           exportKitty,
+	WezTerm:         exportWezTerm,
+	Foot:            exportFoot,
+	Ghostty:         exportGhostty,
+	Xresources:      exportXresources,
+	WindowsTerminal: exportWindowsTerminal,
+}
+
+// importer reads the palette of a theme in a format.
+type importer func(content []byte) (*palette.Palette, error)
+
+var importers = map[Format]importer{
+	Kitty:           importKitty,
+	WezTerm:         importWezTerm,
+	Xresources:      importXresources,
+	WindowsTerminal: importWindowsTerminal,
+	ITerm2:          importITerm2,
+	Base16:          importBase16,
+	Base24:          importBase24,
+}
+
+// Scheme is one of the palettes of a file holding several, by name.
+type Scheme struct {
+	Name    string
+	Palette *palette.Palette
+}
+
+// schemesImporter reads every palette of a file in a format holding
+// several.
+type schemesImporter func(content []byte) ([]Scheme, error)
+
+var schemesImporters = map[Format]schemesImporter{
+	WindowsTerminal: importWindowsTerminalSchemes,
+}
+
+// extensions are the file extensions of the formats, the usual one
+// first, ghostty themes have none. Base24 schemes are read as base16
+// ones, which tell them apart.
+var extensions = map[Format][]string{
+	Kitty:           {".conf"},
+	WezTerm:         {".toml"},
+	Foot:            {".ini"},
+	Xresources:      {".Xresources"},
+	WindowsTerminal: {".json"},
+	ITerm2:          {".itermcolors"},
+	Base16:          {".yaml", ".yml"},
+}
+
+// sortedFormats lists the formats of converters, sorted.
+func sortedFormats[T any](converters map[Format]T) []Format {
+	formats := make([]Format, 0, len(converters))
+	for format := range converters {
+		formats = append(formats, format)
+	}
+
+	slices.Sort(formats)
+
+	return formats
+}
+
+func parseFormat[T any](value string, converters map[Format]T) (Format, error) {
+	format := Format(strings.ToLower(value))
+	if _, ok := converters[format]; !ok {
+		names := make([]string, 0, len(converters))
+		for _, known := range sortedFormats(converters) {
+			names = append(names, string(known))
+		}
+
+		return "", fmt.Errorf("%w %q, use one of %s", ErrUnknownFormat, value, strings.Join(names, ", "))
+	}
+
+	return format, nil
+}
+
+// ExportFormats lists the formats themes can be exported to, sorted.
+func ExportFormats() []Format {
+	return sortedFormats(exporters)
+}
+
+// ImportFormats lists the formats themes can be imported from, sorted.
+func ImportFormats() []Format {
+	return sortedFormats(importers)
+}
+
+// ParseExportFormat reads a format themes can be exported to.
+func ParseExportFormat(value string) (Format, error) {
+	return parseFormat(value, exporters)
+}
+
+// ParseImportFormat reads a format themes can be imported from.
+func ParseImportFormat(value string) (Format, error) {
+	return parseFormat(value, importers)
+}
+
+// DetectFormat guesses the format of the theme file path from its
+// extension, among the formats themes can be imported from.
+func DetectFormat(path string) (Format, error) {
+	extension := filepath.Ext(path)
+	for _, format := range ImportFormats() {
+		for _, known := range extensions[format] {
+			if extension != "" && strings.EqualFold(known, extension) {
+				return format, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w of %s, set it with --from", ErrUnknownFormat, filepath.Base(path))
+}
+
+// Extension returns the usual file extension of the format.
+func (f Format) Extension() string {
+	if len(extensions[f]) == 0 {
+		return ""
+	}
+
+	return extensions[f][0]
+}
+
+// Export writes the palette of the theme name in format.
+func Export(w io.Writer, format Format, name string, p *palette.Palette) error {
+	export, ok := exporters[format]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+
+	return export(w, name, p)
+}
+
+// Import reads the palette of content, a theme in format.
+func Import(format Format, content []byte) (*palette.Palette, error) {
+	read, ok := importers[format]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+
+	return read(content)
+}
+
+// ImportSchemes reads every palette of content, a theme in format. The
+// palette of a format holding a single one has no name.
+func ImportSchemes(format Format, content []byte) ([]Scheme, error) {
+	if read, ok := schemesImporters[format]; ok {
+		return read(content)
+	}
+
+	p, err := Import(format, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Scheme{{Palette: p}}, nil
+}
+
+// colorKeys name the colors of a palette in a format. The ANSI colors go
+// black to white, normal then bright, the other colors are left out when
+// the format doesn't have them.
+type colorKeys struct {
+	background          string
+	foreground          string
+	ansi                [16]string
+	cursor              string
+	cursorText          string
+	selectionBackground string
+	selectionText       string
+}
+
+// readPalette reads the colors named by keys in values with parse. The
+// optional colors left out fall back to what Alacritty would draw.
+func readPalette(values map[string]string, keys colorKeys, parse func(string) (palette.Color, error)) (*palette.Palette, error) {
+	p := newPalette()
+
+	required := []colorSlot{{keys.background, &p.Background}, {keys.foreground, &p.Foreground}}
+	for i := range p.Normal {
+		required = append(required, colorSlot{keys.ansi[i], &p.Normal[i]})
+	}
+
+	for i := range p.Bright {
+		required = append(required, colorSlot{keys.ansi[i+8], &p.Bright[i]})
+	}
+
+	var err error
+	for _, slot := range required {
+		value, ok := values[slot.key]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrMissingColor, slot.key)
+		}
+
+		*slot.color, err = parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slot.key, err)
+		}
+	}
+
+	optional := []struct {
+		colorSlot
+		fallback palette.Color
+	}{
+		{colorSlot{keys.cursor, &p.Cursor}, p.Foreground},
+		{colorSlot{keys.cursorText, &p.CursorText}, p.Background},
+		{colorSlot{keys.selectionBackground, &p.SelectionBackground}, p.Foreground},
+		{colorSlot{keys.selectionText, &p.SelectionText}, p.Background},
+	}
+
+	for _, slot := range optional {
+		*slot.color = slot.fallback
+
+		value, ok := values[slot.key]
+		if slot.key == "" || !ok {
+			continue
+		}
+
+		*slot.color, err = parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slot.key, err)
+		}
+	}
+
+	return p, nil
+}
+
+// colorSlot is the key of a color in a theme and where it goes in the
+// palette.
+type colorSlot struct {
+	key   string
+	color *palette.Color
+}
+
+// newPalette returns a palette with the search colors Alacritty uses by
+// default, which the other terminals don't set.
+func newPalette() *palette.Palette {
+	return &palette.Palette{
+		MatchBackground:   palette.DefaultMatchBackground,
+		MatchText:         palette.DefaultMatchText,
+		FocusedBackground: palette.DefaultFocusedBackground,
+		FocusedText:       palette.DefaultFocusedText,
+	}
+}
+
+// bare returns the color as "rrggbb", as foot writes them.
+func bare(color palette.Color) string {
+	return strings.TrimPrefix(color.Hex(), "#")
+}
@@ -0,0 +1,52 @@
+package formats
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestExport(t *testing.T) {
+	themePalette, err := themes.LoadPalette(filepath.Join("..", "..", "themes", "Nord.toml"))
+	require.NoError(t, err)
+
+	for _, format := range ExportFormats() {
+		t.Run(string(format), func(t *testing.T) {
+			c := require.New(t)
+
+			out := &bytes.Buffer{}
+			c.NoError(Export(out, format, "Nord", themePalette))
+
+			golden := filepath.Join("testdata", "Nord."+string(format)+".golden")
+			if *update {
+				c.NoError(os.WriteFile(golden, out.Bytes(), 0o644))
+			}
+
+			expected, err := os.ReadFile(golden)
+			c.NoError(err)
+			c.Equal(string(expected), out.String())
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	c := require.New(t)
+
+	format, err := ParseExportFormat("Kitty")
+	c.NoError(err)
+	c.Equal(Kitty, format)
+	c.Equal(".conf", format.Extension())
+
+	_, err = ParseExportFormat("iterm2")
+	c.ErrorIs(err, ErrUnknownFormat)
+	c.ErrorContains(err, "use one of foot, ghostty, kitty, wezterm, windows-terminal, xresources")
+
+	c.ErrorIs(Export(&bytes.Buffer{}, "iterm", "Nord", nil), ErrUnknownFormat)
+}
@@ -0,0 +1,27 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+func exportGhostty(w io.Writer, name string, p *palette.Palette) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "# %s, exported by altie\n\n", name)
+	for i, color := range p.ANSI() {
+		fmt.Fprintf(out, "palette = %d=%s\n", i, color.Color.Hex())
+	}
+
+	fmt.Fprintf(out, "background = %s\n", p.Background.Hex())
+	fmt.Fprintf(out, "foreground = %s\n", p.Foreground.Hex())
+	fmt.Fprintf(out, "cursor-color = %s\n", p.Cursor.Hex())
+	fmt.Fprintf(out, "cursor-text = %s\n", p.CursorText.Hex())
+	fmt.Fprintf(out, "selection-background = %s\n", p.SelectionBackground.Hex())
+	fmt.Fprintf(out, "selection-foreground = %s\n", p.SelectionText.Hex())
+
+	return out.Flush()
+}
@@ -0,0 +1,136 @@
+package formats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportExported(t *testing.T) {
+	nord, err := themes.LoadPalette(filepath.Join("..", "..", "themes", "Nord.toml"))
+	require.NoError(t, err)
+
+	// The exported themes read back the same
+	for _, format := range []Format{Kitty, WezTerm, Xresources, WindowsTerminal} {
+		t.Run(string(format), func(t *testing.T) {
+			c := require.New(t)
+
+			content, err := os.ReadFile(filepath.Join("testdata", "Nord."+string(format)+".golden"))
+			c.NoError(err)
+
+			imported, err := Import(format, content)
+			c.NoError(err)
+			c.Equal(nord, imported)
+		})
+	}
+}
+
+func TestImportKitty(t *testing.T) {
+	c := require.New(t)
+
+	content := "# Theme\nfont_size 12\nbackground\t#101010\nforeground  #e0e0e0\n" +
+		"cursor #ff0000\ncursor_text_color background\nselection_foreground none\n"
+
+	_, err := Import(Kitty, []byte(content))
+	c.ErrorIs(err, ErrMissingColor)
+	c.ErrorContains(err, "color0")
+
+	for i, hex := range []string{"#000000", "#800000", "#008000", "#808000", "#000080", "#800080", "#008080", "#c0c0c0"} {
+		content += fmt.Sprintf("color%d %s\ncolor%d %s\n", i, hex, i+8, hex)
+	}
+
+	imported, err := Import(Kitty, []byte(content))
+	c.NoError(err)
+	c.Equal("#101010", imported.Background.Hex())
+	c.Equal("#ff0000", imported.Cursor.Hex())
+	c.Equal(imported.Background, imported.CursorText)
+	c.Equal(imported.Background, imported.SelectionText)
+	c.Equal("#800000", imported.Normal[1].Hex())
+	c.Equal("#c0c0c0", imported.Bright[7].Hex())
+}
+
+func TestImportXresources(t *testing.T) {
+	c := require.New(t)
+
+	content := []byte(`! Solarized
+#define S_base03 #002b36
+#define S_base0 #839496
+#include "other"
+
+URxvt*background: S_base03
+*foreground:      S_base0
+XTerm*cursorColor: rgb:ff/0/8080
+*.color0: #073642
+*.color1: #dc322f
+*.color2: #859900
+*.color3: #b58900
+*.color4: #268bd2
+*.color5: #d33682
+*.color6: #2aa198
+*.color7: #eee8d5
+*.color8: #002b36
+*.color9: #cb4b16
+*.color10: #586e75
+*.color11: #657b83
+*.color12: #839496
+*.color13: #6c71c4
+*.color14: #93a1a1
+*color15: #fdf6e3
+`)
+
+	imported, err := Import(Xresources, content)
+	c.NoError(err)
+	c.Equal("#002b36", imported.Background.Hex())
+	c.Equal("#839496", imported.Foreground.Hex())
+	c.Equal(palette.Color{R: 0xff, G: 0, B: 0x80}, imported.Cursor)
+	c.Equal("#fdf6e3", imported.Bright[7].Hex())
+
+	color, err := parseXColor("rgb:f/8/0")
+	c.NoError(err)
+	c.Equal(palette.Color{R: 0xff, G: 0x88, B: 0}, color)
+
+	_, err = parseXColor("rgb:ff/00")
+	c.ErrorIs(err, palette.ErrInvalidColor)
+
+	_, err = parseXColor("rgb:fffff/0/0")
+	c.ErrorIs(err, palette.ErrInvalidColor)
+}
+
+func TestImportWindowsTerminal(t *testing.T) {
+	c := require.New(t)
+
+	nord, err := os.ReadFile(filepath.Join("testdata", "Nord.windows-terminal.golden"))
+	c.NoError(err)
+
+	settings := []byte(`// Windows Terminal settings
+{
+    "defaultProfile": "{61c54bbd}", /* "schemes": [] */
+    "schemes": [
+        ` + string(nord) + `,
+        ` + string(nord[:len(nord)-2]) + `, "name": "Nord // copy"}
+    ]
+}`)
+
+	schemes, err := ImportSchemes(WindowsTerminal, settings)
+	c.NoError(err)
+	c.Len(schemes, 2)
+	c.Equal("Nord", schemes[0].Name)
+	c.Equal("Nord // copy", schemes[1].Name)
+	c.Equal("#2e3440", schemes[1].Palette.Background.Hex())
+
+	schemes, err = ImportSchemes(WindowsTerminal, []byte("["+string(nord)+"]"))
+	c.NoError(err)
+	c.Len(schemes, 1)
+
+	_, err = Import(WindowsTerminal, []byte(`{"defaultProfile": "{61c54bbd}"}`))
+	c.ErrorIs(err, ErrNoScheme)
+
+	schemes, err = ImportSchemes(Kitty, []byte{})
+	c.ErrorIs(err, ErrMissingColor)
+	c.Nil(schemes)
+}
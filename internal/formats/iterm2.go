@@ -0,0 +1,114 @@
+package formats
+
+import (
+	"fmt"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// Color spaces of the iTerm2 colors, Calibrated when it isn't set
+const (
+	colorSpaceSRGB       = "sRGB"
+	colorSpaceCalibrated = "Calibrated"
+	colorSpaceP3         = "P3"
+	colorSpaceDevice     = "Device"
+)
+
+// importITerm2 reads the colors of an iTerm2 .itermcolors property list.
+func importITerm2(content []byte) (*palette.Palette, error) {
+	root, err := decodePlist(content)
+	if err != nil {
+		return nil, err
+	}
+
+	colors, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: the colors aren't a dictionary", ErrInvalidPlist)
+	}
+
+	p := newPalette()
+
+	required := []colorSlot{
+		{"Background Color", &p.Background},
+		{"Foreground Color", &p.Foreground},
+	}
+
+	for i := range p.Normal {
+		required = append(required,
+			colorSlot{fmt.Sprintf("Ansi %d Color", i), &p.Normal[i]},
+			colorSlot{fmt.Sprintf("Ansi %d Color", i+8), &p.Bright[i]},
+		)
+	}
+
+	for _, slot := range required {
+		value, ok := colors[slot.key]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrMissingColor, slot.key)
+		}
+
+		*slot.color, err = iTerm2Color(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slot.key, err)
+		}
+	}
+
+	optional := []struct {
+		key      string
+		fallback palette.Color
+		color    *palette.Color
+	}{
+		{"Cursor Color", p.Foreground, &p.Cursor},
+		{"Cursor Text Color", p.Background, &p.CursorText},
+		{"Selection Color", p.Foreground, &p.SelectionBackground},
+		{"Selected Text Color", p.Background, &p.SelectionText},
+	}
+
+	for _, slot := range optional {
+		*slot.color = slot.fallback
+
+		value, ok := colors[slot.key]
+		if !ok {
+			continue
+		}
+
+		*slot.color, err = iTerm2Color(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slot.key, err)
+		}
+	}
+
+	return p, nil
+}
+
+// iTerm2Color converts a color dictionary, with float components in its
+// color space, to sRGB.
+func iTerm2Color(value any) (palette.Color, error) {
+	dict, ok := value.(map[string]any)
+	if !ok {
+		return palette.Color{}, fmt.Errorf("%w: not a color dictionary", palette.ErrInvalidColor)
+	}
+
+	components := [3]float64{}
+	for i, name := range []string{"Red Component", "Green Component", "Blue Component"} {
+		components[i], ok = dict[name].(float64)
+		if !ok {
+			return palette.Color{}, fmt.Errorf("%w: missing %s", palette.ErrInvalidColor, name)
+		}
+	}
+
+	r, g, b := components[0], components[1], components[2]
+
+	space, _ := dict["Color Space"].(string)
+	switch space {
+	case colorSpaceSRGB, colorSpaceDevice:
+		return palette.FromSRGB(r, g, b), nil
+	case colorSpaceCalibrated, "":
+		color, _ := palette.FromGenericRGB(r, g, b)
+		return color, nil
+	case colorSpaceP3:
+		color, _ := palette.FromDisplayP3(r, g, b)
+		return color, nil
+	}
+
+	return palette.Color{}, fmt.Errorf("%w: unknown color space %q", palette.ErrInvalidColor, space)
+}
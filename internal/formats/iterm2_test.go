@@ -0,0 +1,89 @@
+package formats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+// plistColor writes an iTerm2 color dictionary, in space unless it's empty.
+func plistColor(key, space string, r, g, b string) string {
+	color := "<key>" + key + "</key><dict>" +
+		"<key>Red Component</key><real>" + r + "</real>" +
+		"<key>Green Component</key><real>" + g + "</real>" +
+		"<key>Blue Component</key><integer>" + b + "</integer>"
+	if space != "" {
+		color += "<key>Color Space</key><string>" + space + "</string>"
+	}
+
+	return color + "</dict>"
+}
+
+func TestImportITerm2(t *testing.T) {
+	c := require.New(t)
+
+	content, err := os.ReadFile(filepath.Join("testdata", "Nord.itermcolors"))
+	c.NoError(err)
+
+	imported, err := Import(ITerm2, content)
+	c.NoError(err)
+
+	nord, err := themes.LoadPalette(filepath.Join("..", "..", "themes", "Nord.toml"))
+	c.NoError(err)
+	c.Equal(nord, imported)
+
+	// Colors in other spaces are converted to sRGB
+	colors := plistColor("Background Color", "Calibrated", "0.5", "0.5", "0") +
+		plistColor("Foreground Color", "P3", "1", "0", "0") +
+		plistColor("Cursor Color", "", "1", "1", "1") +
+		plistColor("Selection Color", "sRGB", "0", "0", "1")
+
+	_, err = Import(ITerm2, []byte("<plist><dict>"+colors+"</dict></plist>"))
+	c.ErrorIs(err, ErrMissingColor)
+	c.ErrorContains(err, "Ansi 0 Color")
+
+	for i := 0; i < 16; i++ {
+		colors += plistColor(fmt.Sprintf("Ansi %d Color", i), "sRGB", "0", "0", "0")
+	}
+
+	imported, err = Import(ITerm2, []byte("<plist><dict>"+colors+"</dict></plist>"))
+	c.NoError(err)
+	c.Equal(palette.Color{R: 146, G: 144, B: 0}, imported.Background)
+	c.Equal(palette.Color{R: 255, G: 0, B: 0}, imported.Foreground)
+	c.Equal(palette.Color{R: 255, G: 255, B: 255}, imported.Cursor)
+	c.Equal(imported.Background, imported.CursorText)
+	c.Equal(palette.Color{R: 0, G: 0, B: 255}, imported.SelectionBackground)
+	c.Equal(palette.DefaultMatchBackground, imported.MatchBackground)
+
+	colors = strings.Replace(colors, "<string>P3</string>", "<string>CMYK</string>", 1)
+	_, err = Import(ITerm2, []byte("<plist><dict>"+colors+"</dict></plist>"))
+	c.ErrorIs(err, palette.ErrInvalidColor)
+	c.ErrorContains(err, "Foreground Color")
+
+	_, err = Import(ITerm2, []byte(`<?xml version="1.0"?><dict></dict>`))
+	c.ErrorIs(err, ErrInvalidPlist)
+
+	_, err = Import(ITerm2, []byte(`<plist><dict><key>Background Color</key><real>zero</real></dict></plist>`))
+	c.ErrorIs(err, ErrInvalidPlist)
+}
+
+func TestDetectFormat(t *testing.T) {
+	c := require.New(t)
+
+	format, err := DetectFormat("/tmp/Nord.itermcolors")
+	c.NoError(err)
+	c.Equal(ITerm2, format)
+
+	_, err = DetectFormat("Nord")
+	c.ErrorIs(err, ErrUnknownFormat)
+
+	format, err = ParseImportFormat("iTerm2")
+	c.NoError(err)
+	c.Equal(ITerm2, format)
+}
@@ -0,0 +1,72 @@
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+var kittyKeys = colorKeys{
+	background:          "background",
+	foreground:          "foreground",
+	cursor:              "cursor",
+	cursorText:          "cursor_text_color",
+	selectionBackground: "selection_background",
+	selectionText:       "selection_foreground",
+}
+
+func init() {
+	for i := range kittyKeys.ansi {
+		kittyKeys.ansi[i] = fmt.Sprintf("color%d", i)
+	}
+}
+
+func exportKitty(w io.Writer, name string, p *palette.Palette) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "# %s, exported by altie\n\n", name)
+	fmt.Fprintf(out, "foreground %s\n", p.Foreground.Hex())
+	fmt.Fprintf(out, "background %s\n", p.Background.Hex())
+	fmt.Fprintf(out, "selection_foreground %s\n", p.SelectionText.Hex())
+	fmt.Fprintf(out, "selection_background %s\n", p.SelectionBackground.Hex())
+	fmt.Fprintf(out, "cursor %s\n", p.Cursor.Hex())
+	fmt.Fprintf(out, "cursor_text_color %s\n\n", p.CursorText.Hex())
+
+	for i, color := range p.ANSI() {
+		fmt.Fprintf(out, "# %s\ncolor%d %s\n", color.Name, i, color.Color.Hex())
+	}
+
+	return out.Flush()
+}
+
+// importKitty reads the colors of a kitty config, the other options are
+// left out. Colors kitty draws from the cell, "none" or "background",
+// fall back like Alacritty.
+func importKitty(content []byte) (*palette.Palette, error) {
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[1] == "none" || fields[1] == "background" {
+			continue
+		}
+
+		values[fields[0]] = fields[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return readPalette(values, kittyKeys, palette.ParseHex)
+}
@@ -0,0 +1,129 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPlist = errors.New("invalid property list")
+
+// decodePlist reads an XML property list into maps, slices, strings,
+// floats and bools.
+func decodePlist(content []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	inPlist := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPlist, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if !inPlist {
+			if start.Name.Local != "plist" {
+				return nil, fmt.Errorf("%w: expected <plist>, got <%s>", ErrInvalidPlist, start.Name.Local)
+			}
+
+			inPlist = true
+
+			continue
+		}
+
+		return decodePlistValue(decoder, start)
+	}
+}
+
+func decodePlistValue(decoder *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		return decodePlistDict(decoder)
+	case "array":
+		return decodePlistArray(decoder)
+	case "true", "false":
+		return start.Name.Local == "true", decoder.Skip()
+	}
+
+	var text string
+	err := decoder.DecodeElement(&text, &start)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPlist, err)
+	}
+
+	text = strings.TrimSpace(text)
+
+	switch start.Name.Local {
+	case "string", "data", "date":
+		return text, nil
+	case "real", "integer":
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: <%s> %q", ErrInvalidPlist, start.Name.Local, text)
+		}
+
+		return number, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown <%s>", ErrInvalidPlist, start.Name.Local)
+}
+
+func decodePlistDict(decoder *xml.Decoder) (map[string]any, error) {
+	dict := make(map[string]any)
+
+	key := ""
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPlist, err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if element.Name.Local == "key" {
+				err = decoder.DecodeElement(&key, &element)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrInvalidPlist, err)
+				}
+
+				continue
+			}
+
+			dict[key], err = decodePlistValue(decoder, element)
+			if err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			return dict, nil
+		}
+	}
+}
+
+func decodePlistArray(decoder *xml.Decoder) ([]any, error) {
+	array := make([]any, 0)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPlist, err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			value, err := decodePlistValue(decoder, element)
+			if err != nil {
+				return nil, err
+			}
+
+			array = append(array, value)
+		case xml.EndElement:
+			return array, nil
+		}
+	}
+}
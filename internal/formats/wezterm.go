@@ -0,0 +1,104 @@
+package formats
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// wezTermScheme is a color scheme file of the WezTerm colors directory.
+type wezTermScheme struct {
+	Colors struct {
+		Foreground   string    `toml:"foreground"`
+		Background   string    `toml:"background"`
+		CursorBg     string    `toml:"cursor_bg"`
+		CursorBorder string    `toml:"cursor_border"`
+		CursorFg     string    `toml:"cursor_fg"`
+		SelectionBg  string    `toml:"selection_bg"`
+		SelectionFg  string    `toml:"selection_fg"`
+		ANSI         [8]string `toml:"ansi"`
+		Brights      [8]string `toml:"brights"`
+	} `toml:"colors"`
+	Metadata struct {
+		Name string `toml:"name"`
+	} `toml:"metadata"`
+}
+
+func exportWezTerm(w io.Writer, name string, p *palette.Palette) error {
+	scheme := wezTermScheme{}
+	scheme.Colors.Foreground = p.Foreground.Hex()
+	scheme.Colors.Background = p.Background.Hex()
+	scheme.Colors.CursorBg = p.Cursor.Hex()
+	scheme.Colors.CursorBorder = p.Cursor.Hex()
+	scheme.Colors.CursorFg = p.CursorText.Hex()
+	scheme.Colors.SelectionBg = p.SelectionBackground.Hex()
+	scheme.Colors.SelectionFg = p.SelectionText.Hex()
+
+	for i := range p.Normal {
+		scheme.Colors.ANSI[i] = p.Normal[i].Hex()
+		scheme.Colors.Brights[i] = p.Bright[i].Hex()
+	}
+
+	scheme.Metadata.Name = name
+
+	fmt.Fprintf(w, "# %s, exported by altie\n\n", name)
+
+	encoder := toml.NewEncoder(w)
+	encoder.Indent = ""
+
+	err := encoder.Encode(scheme)
+	if err != nil {
+		return fmt.Errorf("failed to encode the WezTerm scheme: %w", err)
+	}
+
+	return nil
+}
+
+var wezTermKeys = colorKeys{
+	background:          "background",
+	foreground:          "foreground",
+	cursor:              "cursor_bg",
+	cursorText:          "cursor_fg",
+	selectionBackground: "selection_bg",
+	selectionText:       "selection_fg",
+}
+
+func init() {
+	for i := range palette.ANSINames {
+		wezTermKeys.ansi[i] = fmt.Sprintf("ansi[%d]", i)
+		wezTermKeys.ansi[i+8] = fmt.Sprintf("brights[%d]", i)
+	}
+}
+
+// importWezTerm reads a color scheme of the WezTerm colors directory.
+func importWezTerm(content []byte) (*palette.Palette, error) {
+	scheme := wezTermScheme{}
+	if _, err := toml.Decode(string(content), &scheme); err != nil {
+		return nil, err
+	}
+
+	colors := scheme.Colors
+	values := map[string]string{
+		"background":   colors.Background,
+		"foreground":   colors.Foreground,
+		"cursor_bg":    colors.CursorBg,
+		"cursor_fg":    colors.CursorFg,
+		"selection_bg": colors.SelectionBg,
+		"selection_fg": colors.SelectionFg,
+	}
+
+	for i := range colors.ANSI {
+		values[fmt.Sprintf("ansi[%d]", i)] = colors.ANSI[i]
+		values[fmt.Sprintf("brights[%d]", i)] = colors.Brights[i]
+	}
+
+	for key, value := range values {
+		if value == "" {
+			delete(values, key)
+		}
+	}
+
+	return readPalette(values, wezTermKeys, palette.ParseHex)
+}
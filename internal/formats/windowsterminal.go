@@ -0,0 +1,217 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// windowsTerminalScheme is an entry of the "schemes" list of the Windows
+// Terminal settings, which calls magenta purple.
+type windowsTerminalScheme struct {
+	Name                string `json:"name"`
+	Background          string `json:"background"`
+	Foreground          string `json:"foreground"`
+	CursorColor         string `json:"cursorColor"`
+	SelectionBackground string `json:"selectionBackground"`
+	Black               string `json:"black"`
+	Red                 string `json:"red"`
+	Green               string `json:"green"`
+	Yellow              string `json:"yellow"`
+	Blue                string `json:"blue"`
+	Purple              string `json:"purple"`
+	Cyan                string `json:"cyan"`
+	White               string `json:"white"`
+	BrightBlack         string `json:"brightBlack"`
+	BrightRed           string `json:"brightRed"`
+	BrightGreen         string `json:"brightGreen"`
+	BrightYellow        string `json:"brightYellow"`
+	BrightBlue          string `json:"brightBlue"`
+	BrightPurple        string `json:"brightPurple"`
+	BrightCyan          string `json:"brightCyan"`
+	BrightWhite         string `json:"brightWhite"`
+}
+
+func exportWindowsTerminal(w io.Writer, name string, p *palette.Palette) error {
+	scheme := windowsTerminalScheme{
+		Name:                name,
+		Background:          p.Background.Hex(),
+		Foreground:          p.Foreground.Hex(),
+		CursorColor:         p.Cursor.Hex(),
+		SelectionBackground: p.SelectionBackground.Hex(),
+		Black:               p.Normal[0].Hex(),
+		Red:                 p.Normal[1].Hex(),
+		Green:               p.Normal[2].Hex(),
+		Yellow:              p.Normal[3].Hex(),
+		Blue:                p.Normal[4].Hex(),
+		Purple:              p.Normal[5].Hex(),
+		Cyan:                p.Normal[6].Hex(),
+		White:               p.Normal[7].Hex(),
+		BrightBlack:         p.Bright[0].Hex(),
+		BrightRed:           p.Bright[1].Hex(),
+		BrightGreen:         p.Bright[2].Hex(),
+		BrightYellow:        p.Bright[3].Hex(),
+		BrightBlue:          p.Bright[4].Hex(),
+		BrightPurple:        p.Bright[5].Hex(),
+		BrightCyan:          p.Bright[6].Hex(),
+		BrightWhite:         p.Bright[7].Hex(),
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(scheme)
+}
+
+var ErrNoScheme = errors.New("no color scheme found")
+
+var windowsTerminalKeys = colorKeys{
+	background:          "background",
+	foreground:          "foreground",
+	cursor:              "cursorColor",
+	selectionBackground: "selectionBackground",
+	ansi: [16]string{
+		"black", "red", "green", "yellow", "blue", "purple", "cyan", "white",
+		"brightBlack", "brightRed", "brightGreen", "brightYellow", "brightBlue", "brightPurple", "brightCyan", "brightWhite",
+	},
+}
+
+// values lists the colors the scheme sets by their JSON names.
+func (scheme windowsTerminalScheme) values() map[string]string {
+	all := map[string]string{
+		"background":          scheme.Background,
+		"foreground":          scheme.Foreground,
+		"cursorColor":         scheme.CursorColor,
+		"selectionBackground": scheme.SelectionBackground,
+	}
+
+	ansi := [16]string{
+		scheme.Black, scheme.Red, scheme.Green, scheme.Yellow, scheme.Blue, scheme.Purple, scheme.Cyan, scheme.White,
+		scheme.BrightBlack, scheme.BrightRed, scheme.BrightGreen, scheme.BrightYellow,
+		scheme.BrightBlue, scheme.BrightPurple, scheme.BrightCyan, scheme.BrightWhite,
+	}
+	for i, key := range windowsTerminalKeys.ansi {
+		all[key] = ansi[i]
+	}
+
+	values := make(map[string]string, len(all))
+	for key, value := range all {
+		if value != "" {
+			values[key] = value
+		}
+	}
+
+	return values
+}
+
+// stripJSONComments blanks the // and /* */ comments the Windows Terminal
+// settings allow, leaving the strings alone.
+func stripJSONComments(content []byte) []byte {
+	stripped := bytes.Clone(content)
+
+	inString := false
+	for i := 0; i < len(stripped); i++ {
+		switch {
+		case inString && stripped[i] == '\\':
+			i++
+		case stripped[i] == '"':
+			inString = !inString
+		case inString:
+		case bytes.HasPrefix(stripped[i:], []byte("//")):
+			for ; i < len(stripped) && stripped[i] != '\n'; i++ {
+				stripped[i] = ' '
+			}
+		case bytes.HasPrefix(stripped[i:], []byte("/*")):
+			end := bytes.Index(stripped[i+2:], []byte("*/"))
+			last := len(stripped)
+			if end >= 0 {
+				last = i + 2 + end + 2
+			}
+
+			for ; i < last; i++ {
+				if stripped[i] != '\n' {
+					stripped[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+
+	return stripped
+}
+
+// decodeWindowsTerminal reads the schemes of the Windows Terminal
+// settings, of a list of schemes or a single one.
+func decodeWindowsTerminal(content []byte) ([]windowsTerminalScheme, error) {
+	content = bytes.TrimSpace(stripJSONComments(content))
+
+	schemes := make([]windowsTerminalScheme, 0)
+	if bytes.HasPrefix(content, []byte("[")) {
+		err := json.Unmarshal(content, &schemes)
+		return schemes, err
+	}
+
+	settings := struct {
+		Schemes []windowsTerminalScheme `json:"schemes"`
+	}{}
+	err := json.Unmarshal(content, &settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(settings.Schemes) > 0 {
+		return settings.Schemes, nil
+	}
+
+	scheme := windowsTerminalScheme{}
+	err = json.Unmarshal(content, &scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheme.Background == "" && scheme.Foreground == "" {
+		return schemes, nil
+	}
+
+	return append(schemes, scheme), nil
+}
+
+// importWindowsTerminalSchemes reads every scheme of the Windows Terminal
+// settings.
+func importWindowsTerminalSchemes(content []byte) ([]Scheme, error) {
+	schemes, err := decodeWindowsTerminal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schemes) == 0 {
+		return nil, ErrNoScheme
+	}
+
+	imported := make([]Scheme, 0, len(schemes))
+	for _, scheme := range schemes {
+		p, err := readPalette(scheme.values(), windowsTerminalKeys, palette.ParseHex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scheme.Name, err)
+		}
+
+		imported = append(imported, Scheme{Name: scheme.Name, Palette: p})
+	}
+
+	return imported, nil
+}
+
+// importWindowsTerminal reads the first scheme of the Windows Terminal
+// settings.
+func importWindowsTerminal(content []byte) (*palette.Palette, error) {
+	schemes, err := importWindowsTerminalSchemes(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return schemes[0].Palette, nil
+}
@@ -0,0 +1,109 @@
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+func exportXresources(w io.Writer, name string, p *palette.Palette) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "! %s, exported by altie\n\n", name)
+	fmt.Fprintf(out, "*.foreground: %s\n", p.Foreground.Hex())
+	fmt.Fprintf(out, "*.background: %s\n", p.Background.Hex())
+	fmt.Fprintf(out, "*.cursorColor: %s\n", p.Cursor.Hex())
+
+	for i, color := range p.ANSI() {
+		fmt.Fprintf(out, "\n! %s\n*.color%d: %s\n", color.Name, i, color.Color.Hex())
+	}
+
+	return out.Flush()
+}
+
+var xresourcesKeys = colorKeys{
+	background: "background",
+	foreground: "foreground",
+	cursor:     "cursorColor",
+}
+
+func init() {
+	for i := range xresourcesKeys.ansi {
+		xresourcesKeys.ansi[i] = fmt.Sprintf("color%d", i)
+	}
+}
+
+// importXresources reads the colors of X resources for any class,
+// "*.color0", "*color0" or "URxvt.color0", expanding the #define macros
+// themes often name their colors with. The last definition wins.
+func importXresources(content []byte) (*palette.Palette, error) {
+	defines := make(map[string]string)
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if fields := strings.Fields(line); len(fields) == 3 && fields[0] == "#define" {
+			defines[fields[1]] = fields[2]
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		resource, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if defined, ok := defines[value]; ok {
+			value = defined
+		}
+
+		resource = strings.TrimSpace(resource)
+		values[resource[strings.LastIndexAny(resource, ".*")+1:]] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return readPalette(values, xresourcesKeys, parseXColor)
+}
+
+// parseXColor reads the hex colors and the "rgb:rr/gg/bb" form of X, with
+// one to four hex digits per component.
+func parseXColor(value string) (palette.Color, error) {
+	spec, ok := strings.CutPrefix(strings.ToLower(value), "rgb:")
+	if !ok {
+		return palette.ParseHex(value)
+	}
+
+	components := strings.Split(spec, "/")
+	if len(components) != 3 {
+		return palette.Color{}, fmt.Errorf("%w: %q", palette.ErrInvalidColor, value)
+	}
+
+	channels := [3]float64{}
+	for i, component := range components {
+		if len(component) < 1 || len(component) > 4 {
+			return palette.Color{}, fmt.Errorf("%w: %q", palette.ErrInvalidColor, value)
+		}
+
+		channel, err := strconv.ParseUint(component, 16, 16)
+		if err != nil {
+			return palette.Color{}, fmt.Errorf("%w: %q", palette.ErrInvalidColor, value)
+		}
+
+		channels[i] = float64(channel) / float64(uint64(1)<<(4*len(component))-1)
+	}
+
+	return palette.FromSRGB(channels[0], channels[1], channels[2]), nil
+}
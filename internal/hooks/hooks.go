@@ -0,0 +1,149 @@
+// Package hooks runs the commands configured around applying a theme,
+// like reloading tmux or signalling Neovim.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// Policy tells what a failing hook does to the apply.
+type Policy string
+
+const (
+	// Warn reports the failure and carries on
+	Warn Policy = "warn"
+	// Rollback stops the apply and puts the previous theme back
+	Rollback Policy = "rollback"
+)
+
+// DefaultTimeout bounds each hook when altie.conf doesn't.
+const DefaultTimeout = 10 * time.Second
+
+// waitDelay is how long a hook that timed out may keep its output open,
+// through a child it started, before altie stops waiting for it.
+const waitDelay = time.Second
+
+var (
+	ErrInvalidPolicy  = errors.New("hooks OnFailure must be warn or rollback")
+	ErrInvalidTimeout = errors.New("hooks Timeout must be a positive duration like 5s")
+	ErrHookFailed     = errors.New("hook failed")
+)
+
+// Hooks are the validated hooks of altie.conf.
+type Hooks struct {
+	PreApply  []string
+	PostApply []string
+	Timeout   time.Duration
+	Policy    Policy
+}
+
+// Result is what a hook printed, standard output and error together, and
+// how it ended.
+type Result struct {
+	Command string
+	Output  string
+	Err     error
+}
+
+// Parse validates the hooks configured in altie.conf, they warn after 10
+// seconds by default.
+func Parse(cfg config.Hooks) (Hooks, error) {
+	h := Hooks{
+		PreApply:  cfg.PreApply,
+		PostApply: cfg.PostApply,
+		Timeout:   DefaultTimeout,
+		Policy:    Warn,
+	}
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return Hooks{}, fmt.Errorf("%w: %q", ErrInvalidTimeout, cfg.Timeout)
+		}
+
+		h.Timeout = timeout
+	}
+
+	switch Policy(cfg.OnFailure) {
+	case "":
+	case Warn, Rollback:
+		h.Policy = Policy(cfg.OnFailure)
+	default:
+		return Hooks{}, fmt.Errorf("%w: %q", ErrInvalidPolicy, cfg.OnFailure)
+	}
+
+	return h, nil
+}
+
+// Configured reports whether there is any hook to run.
+func (h Hooks) Configured() bool {
+	return len(h.PreApply) > 0 || len(h.PostApply) > 0
+}
+
+// Env describes the theme being applied to the hooks, previous is the
+// theme applied before it, empty for the first one.
+func Env(name, themePath, previous string, p *palette.Palette) []string {
+	env := []string{
+		"ALTIE_THEME=" + name,
+		"ALTIE_THEME_PATH=" + themePath,
+		"ALTIE_PREVIOUS_THEME=" + previous,
+		"ALTIE_VARIANT=" + string(p.Variant()),
+		"ALTIE_BG=" + p.Background.Hex(),
+		"ALTIE_FG=" + p.Foreground.Hex(),
+		"ALTIE_CURSOR=" + p.Cursor.Hex(),
+		"ALTIE_SELECTION_BG=" + p.SelectionBackground.Hex(),
+		"ALTIE_SELECTION_FG=" + p.SelectionText.Hex(),
+	}
+
+	for i, color := range p.ANSI() {
+		env = append(env, fmt.Sprintf("ALTIE_COLOR%d=%s", i, color.Color.Hex()))
+	}
+
+	return env
+}
+
+// Run runs commands one after the other with sh, env added to the
+// environment of altie, each stopped after timeout. It stops at the first
+// failing command and returns the results of the ones that ran.
+func Run(ctx context.Context, commands []string, env []string, timeout time.Duration) ([]Result, error) {
+	results := make([]Result, 0, len(commands))
+	for _, command := range commands {
+		result := run(ctx, command, env, timeout)
+		results = append(results, result)
+
+		if result.Err != nil {
+			return results, fmt.Errorf("%w: %s: %w", ErrHookFailed, command, result.Err)
+		}
+	}
+
+	return results, nil
+}
+
+func run(ctx context.Context, command string, env []string, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = waitDelay
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	return Result{Command: command, Output: strings.TrimRight(output.String(), "\n"), Err: err}
+}
@@ -0,0 +1,82 @@
+package hooks
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	c := require.New(t)
+
+	h, err := Parse(config.Hooks{PostApply: []string{"tmux source-file ~/.tmux.conf"}})
+	c.NoError(err)
+	c.Equal(Hooks{PostApply: []string{"tmux source-file ~/.tmux.conf"}, Timeout: DefaultTimeout, Policy: Warn}, h)
+	c.True(h.Configured())
+
+	h, err = Parse(config.Hooks{Timeout: "2s", OnFailure: "rollback"})
+	c.NoError(err)
+	c.Equal(2*time.Second, h.Timeout)
+	c.Equal(Rollback, h.Policy)
+	c.False(h.Configured())
+
+	_, err = Parse(config.Hooks{Timeout: "2"})
+	c.ErrorIs(err, ErrInvalidTimeout)
+
+	_, err = Parse(config.Hooks{Timeout: "-1s"})
+	c.ErrorIs(err, ErrInvalidTimeout)
+
+	_, err = Parse(config.Hooks{OnFailure: "ignore"})
+	c.ErrorIs(err, ErrInvalidPolicy)
+}
+
+func TestEnv(t *testing.T) {
+	c := require.New(t)
+
+	themePalette, err := themes.LoadPalette(filepath.Join("..", "..", "themes", "Nord.toml"))
+	c.NoError(err)
+
+	env := Env("Nord.toml", "/themes/Nord.toml", "Dracula.toml", themePalette)
+	c.Contains(env, "ALTIE_THEME=Nord.toml")
+	c.Contains(env, "ALTIE_THEME_PATH=/themes/Nord.toml")
+	c.Contains(env, "ALTIE_PREVIOUS_THEME=Dracula.toml")
+	c.Contains(env, "ALTIE_VARIANT=dark")
+	c.Contains(env, "ALTIE_BG=#2e3440")
+	c.Contains(env, "ALTIE_FG=#d8dee9")
+	c.Contains(env, "ALTIE_COLOR1=#bf616a")
+	c.Contains(env, "ALTIE_COLOR15=#eceff4")
+}
+
+func TestRun(t *testing.T) {
+	c := require.New(t)
+
+	env := []string{"ALTIE_THEME=Nord.toml", "ALTIE_BG=#2e3440"}
+	results, err := Run(context.Background(), []string{
+		`echo "$ALTIE_THEME on $ALTIE_BG"`,
+		"echo warning >&2",
+		"true",
+	}, env, time.Second)
+	c.NoError(err)
+	c.Equal([]Result{
+		{Command: `echo "$ALTIE_THEME on $ALTIE_BG"`, Output: "Nord.toml on #2e3440"},
+		{Command: "echo warning >&2", Output: "warning"},
+		{Command: "true"},
+	}, results)
+
+	// The first failing hook stops the others
+	results, err = Run(context.Background(), []string{"echo reloading; exit 3", "echo never"}, env, time.Second)
+	c.ErrorIs(err, ErrHookFailed)
+	c.EqualError(err, "hook failed: echo reloading; exit 3: exit status 3")
+	c.Len(results, 1)
+	c.Equal("reloading", results[0].Output)
+
+	start := time.Now()
+	_, err = Run(context.Background(), []string{"sleep 5"}, env, 100*time.Millisecond)
+	c.EqualError(err, "hook failed: sleep 5: timed out after 100ms")
+	c.Less(time.Since(start), 2*time.Second)
+}
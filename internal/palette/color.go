@@ -0,0 +1,84 @@
+package palette
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidColor = errors.New("invalid color")
+
+// Color is an 8-bit sRGB color as written in Alacritty themes.
+type Color struct {
+	R uint8
+	G uint8
+	B uint8
+}
+
+// ParseHex reads the color formats used by Alacritty themes:
+// "#rrggbb", "0xrrggbb" and the short "#rgb" form.
+func ParseHex(value string) (Color, error) {
+	hex := strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(hex, "#"):
+		hex = hex[1:]
+	case strings.HasPrefix(hex, "0x"), strings.HasPrefix(hex, "0X"):
+		hex = hex[2:]
+	}
+
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 {
+		return Color{}, fmt.Errorf("%w: %q", ErrInvalidColor, value)
+	}
+
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("%w: %q", ErrInvalidColor, value)
+	}
+
+	return Color{
+		R: uint8(rgb >> 16),
+		G: uint8(rgb >> 8),
+		B: uint8(rgb),
+	}, nil
+}
+
+// Hex returns the color as "#rrggbb".
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Luminance is the WCAG relative luminance of the color, from 0 (black) to 1 (white).
+func (c Color) Luminance() float64 {
+	return 0.2126*linearize(c.R) + 0.7152*linearize(c.G) + 0.0722*linearize(c.B)
+}
+
+func linearize(channel uint8) float64 {
+	v := float64(channel) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// MarshalText writes the color as "#rrggbb" so cached palettes stay readable.
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(c.Hex()), nil
+}
+
+func (c *Color) UnmarshalText(text []byte) error {
+	color, err := ParseHex(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = color
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHex(t *testing.T) {
+	c := require.New(t)
+
+	color, err := ParseHex("#2E3440")
+	c.NoError(err)
+	c.Equal(Color{0x2e, 0x34, 0x40}, color)
+
+	color, err = ParseHex("0xd18616")
+	c.NoError(err)
+	c.Equal(Color{0xd1, 0x86, 0x16}, color)
+
+	color, err = ParseHex(" #fff ")
+	c.NoError(err)
+	c.Equal(Color{0xff, 0xff, 0xff}, color)
+
+	for _, invalid := range []string{"", "CellBackground", "None", "#12345", "#gggggg"} {
+		_, err = ParseHex(invalid)
+		c.ErrorIs(err, ErrInvalidColor, invalid)
+	}
+}
+
+func TestHex(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal("#2e3440", Color{0x2e, 0x34, 0x40}.Hex())
+	c.Equal("#000000", Color{}.Hex())
+}
+
+func TestLuminance(t *testing.T) {
+	c := require.New(t)
+
+	c.InDelta(0, Color{}.Luminance(), 1e-9)
+	c.InDelta(1, Color{255, 255, 255}.Luminance(), 1e-9)
+	c.InDelta(0.2126, Color{255, 0, 0}.Luminance(), 1e-9)
+	c.InDelta(0.2159, Color{128, 128, 128}.Luminance(), 1e-4)
+}
+
+func TestColorText(t *testing.T) {
+	c := require.New(t)
+
+	text, err := Color{0x2e, 0x34, 0x40}.MarshalText()
+	c.NoError(err)
+	c.Equal("#2e3440", string(text))
+
+	color := Color{}
+	c.NoError(color.UnmarshalText([]byte("#d8dee9")))
+	c.Equal(Color{0xd8, 0xde, 0xe9}, color)
+
+	c.ErrorIs(color.UnmarshalText([]byte("None")), ErrInvalidColor)
+}
@@ -0,0 +1,139 @@
+package palette
+
+import "math"
+
+// WCAGAA is the contrast ratio WCAG 2.x level AA requires for body text.
+const WCAGAA = 4.5
+
+// APCABody is the APCA lightness contrast recommended for body text.
+const APCABody = 60.0
+
+// APCA 0.0.98G-4g constants
+const (
+	apcaBlackThreshold = 0.022
+	apcaBlackClamp     = 1.414
+	apcaDeltaYMin      = 0.0005
+	apcaNormBG         = 0.56
+	apcaNormText       = 0.57
+	apcaRevBG          = 0.65
+	apcaRevText        = 0.62
+	apcaScale          = 1.14
+	apcaOffset         = 0.027
+	apcaLowClip        = 0.1
+)
+
+// Pair is a text color drawn over a background color.
+type Pair struct {
+	Name       string
+	Foreground Color
+	Background Color
+}
+
+// Contrast is the WCAG 2.x contrast ratio between two colors, from 1 to 21.
+func Contrast(a, b Color) float64 {
+	lighter, darker := a.Luminance(), b.Luminance()
+	if darker > lighter {
+		lighter, darker = darker, lighter
+	}
+
+	return (lighter + 0.05) / (darker + 0.05)
+}
+
+// APCA is the lightness contrast Lc of text over background. It's positive
+// for dark text on a light background and negative the other way around.
+func APCA(text, background Color) float64 {
+	textY := apcaClamp(apcaLuminance(text))
+	backgroundY := apcaClamp(apcaLuminance(background))
+
+	if math.Abs(backgroundY-textY) < apcaDeltaYMin {
+		return 0
+	}
+
+	if backgroundY > textY {
+		sapc := (math.Pow(backgroundY, apcaNormBG) - math.Pow(textY, apcaNormText)) * apcaScale
+		if sapc < apcaLowClip {
+			return 0
+		}
+
+		return (sapc - apcaOffset) * 100
+	}
+
+	sapc := (math.Pow(backgroundY, apcaRevBG) - math.Pow(textY, apcaRevText)) * apcaScale
+	if sapc > -apcaLowClip {
+		return 0
+	}
+
+	return (sapc + apcaOffset) * 100
+}
+
+func apcaLuminance(c Color) float64 {
+	channel := func(v uint8) float64 {
+		return math.Pow(float64(v)/255, 2.4)
+	}
+
+	return 0.2126729*channel(c.R) + 0.7151522*channel(c.G) + 0.0721750*channel(c.B)
+}
+
+func apcaClamp(y float64) float64 {
+	if y > apcaBlackThreshold {
+		return y
+	}
+
+	return y + math.Pow(apcaBlackThreshold-y, apcaBlackClamp)
+}
+
+// Ratio is the WCAG contrast ratio of the pair.
+func (p Pair) Ratio() float64 {
+	return Contrast(p.Foreground, p.Background)
+}
+
+// APCA is the APCA lightness contrast of the pair.
+func (p Pair) APCA() float64 {
+	return APCA(p.Foreground, p.Background)
+}
+
+type slot struct {
+	name       string
+	text       *Color
+	background *Color
+}
+
+// slots points at the text and background of every pair so fixes can
+// change them in place.
+func (p *Palette) slots() []slot {
+	slots := []slot{{"foreground", &p.Foreground, &p.Background}}
+
+	for i, name := range ANSINames {
+		slots = append(slots, slot{"normal " + name, &p.Normal[i], &p.Background})
+	}
+
+	for i, name := range ANSINames {
+		slots = append(slots, slot{"bright " + name, &p.Bright[i], &p.Background})
+	}
+
+	return append(slots,
+		slot{"selection", &p.SelectionText, &p.SelectionBackground},
+		slot{"cursor", &p.CursorText, &p.Cursor},
+		slot{"search match", &p.MatchText, &p.MatchBackground},
+		slot{"focused match", &p.FocusedText, &p.FocusedBackground},
+	)
+}
+
+// Pairs lists the color combinations a theme draws text with: the primary
+// colors, every ANSI color over the background, the selection, the cursor
+// and search matches.
+func (p *Palette) Pairs() []Pair {
+	slots := p.slots()
+	pairs := make([]Pair, 0, len(slots))
+	for _, slot := range slots {
+		pairs = append(pairs, Pair{slot.name, *slot.text, *slot.background})
+	}
+
+	return pairs
+}
+
+// PrimaryContrast is the contrast ratio of the foreground over the
+// background, the figure themes are ranked by.
+func (p *Palette) PrimaryContrast() float64 {
+	return Contrast(p.Foreground, p.Background)
+}
@@ -0,0 +1,60 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	black = Color{0, 0, 0}
+	white = Color{255, 255, 255}
+)
+
+func TestContrast(t *testing.T) {
+	c := require.New(t)
+
+	c.InDelta(21, Contrast(black, white), 1e-9)
+	c.InDelta(21, Contrast(white, black), 1e-9)
+	c.InDelta(1, Contrast(white, white), 1e-9)
+
+	// #767676 is the lightest gray passing AA on white, #777777 just fails
+	c.GreaterOrEqual(Contrast(Color{0x76, 0x76, 0x76}, white), WCAGAA)
+	c.Less(Contrast(Color{0x77, 0x77, 0x77}, white), WCAGAA)
+}
+
+func TestAPCA(t *testing.T) {
+	c := require.New(t)
+
+	c.InDelta(106.04, APCA(black, white), 0.01)
+	c.InDelta(-107.88, APCA(white, black), 0.01)
+	c.InDelta(0, APCA(white, white), 1e-9)
+
+	gray := Color{0x88, 0x88, 0x88}
+	c.InDelta(63.1, APCA(gray, white), 0.05)
+	c.InDelta(-38.6, APCA(gray, black), 0.05)
+}
+
+func TestPairs(t *testing.T) {
+	c := require.New(t)
+
+	p := &Palette{
+		Background:    Color{0x2e, 0x34, 0x40},
+		Foreground:    Color{0xd8, 0xde, 0xe9},
+		Cursor:        Color{0xd8, 0xde, 0xe9},
+		CursorText:    Color{0x2e, 0x34, 0x40},
+		SelectionText: Color{0x2e, 0x34, 0x40},
+	}
+	p.Normal[1] = Color{0xbf, 0x61, 0x6a}
+
+	pairs := p.Pairs()
+	c.Len(pairs, 21)
+	c.Equal(Pair{"foreground", p.Foreground, p.Background}, pairs[0])
+	c.Equal(Pair{"normal red", p.Normal[1], p.Background}, pairs[2])
+	c.Equal("bright white", pairs[16].Name)
+	c.Equal(Pair{"cursor", p.CursorText, p.Cursor}, pairs[18])
+
+	c.InDelta(Contrast(p.Foreground, p.Background), p.PrimaryContrast(), 1e-9)
+	c.InDelta(p.PrimaryContrast(), pairs[0].Ratio(), 1e-9)
+	c.InDelta(APCA(p.Foreground, p.Background), pairs[0].APCA(), 1e-9)
+}
@@ -0,0 +1,112 @@
+package palette
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	Protanopia   Deficiency = "protanopia"
+	Deuteranopia Deficiency = "deuteranopia"
+	Tritanopia   Deficiency = "tritanopia"
+)
+
+// Distinguishable is the CIEDE2000 difference under which two colors are
+// hard to tell apart at text size.
+const Distinguishable = 10.0
+
+var ErrInvalidDeficiency = errors.New("type must be protanopia, deuteranopia or tritanopia")
+
+// Deficiency is a kind of color vision deficiency.
+type Deficiency string
+
+// Machado, Oliveira and Fernandes (2009) matrices at full severity,
+// applied to linear sRGB.
+var deficiencyMatrices = map[Deficiency][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// Confusion is a pair of ANSI colors that look alike under a deficiency.
+type Confusion struct {
+	First  string
+	Second string
+	Before float64
+	After  float64
+}
+
+func ParseDeficiency(value string) (Deficiency, error) {
+	if _, ok := deficiencyMatrices[Deficiency(value)]; ok {
+		return Deficiency(value), nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidDeficiency, value)
+}
+
+// Simulate returns how the color looks to someone with the deficiency.
+func (d Deficiency) Simulate(c Color) Color {
+	m := deficiencyMatrices[d]
+	r, g, b := c.linear()
+
+	simulated, _ := fromLinear(
+		m[0][0]*r+m[0][1]*g+m[0][2]*b,
+		m[1][0]*r+m[1][1]*g+m[1][2]*b,
+		m[2][0]*r+m[2][1]*g+m[2][2]*b,
+	)
+
+	return simulated
+}
+
+// Simulate returns the palette as seen with the deficiency.
+func (p *Palette) Simulate(d Deficiency) *Palette {
+	simulated := *p
+	for _, slot := range simulated.slots() {
+		*slot.text = d.Simulate(*slot.text)
+	}
+
+	simulated.Background = d.Simulate(p.Background)
+	simulated.SelectionBackground = d.Simulate(p.SelectionBackground)
+	simulated.Cursor = d.Simulate(p.Cursor)
+	simulated.MatchBackground = d.Simulate(p.MatchBackground)
+	simulated.FocusedBackground = d.Simulate(p.FocusedBackground)
+
+	return &simulated
+}
+
+// Confusions lists the ANSI color pairs that are distinguishable in the
+// palette but fall under threshold once simulated with the deficiency.
+func (p *Palette) Confusions(d Deficiency, threshold float64) []Confusion {
+	original := p.ANSI()
+	simulated := p.Simulate(d).ANSI()
+
+	confusions := make([]Confusion, 0)
+	for i := range original {
+		for j := i + 1; j < len(original); j++ {
+			before := DeltaE(original[i].Color, original[j].Color)
+			after := DeltaE(simulated[i].Color, simulated[j].Color)
+			if before >= threshold && after < threshold {
+				confusions = append(confusions, Confusion{
+					First:  original[i].Name,
+					Second: original[j].Name,
+					Before: before,
+					After:  after,
+				})
+			}
+		}
+	}
+
+	return confusions
+}
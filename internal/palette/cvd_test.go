@@ -0,0 +1,84 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeficiency(t *testing.T) {
+	c := require.New(t)
+
+	for _, deficiency := range []Deficiency{Protanopia, Deuteranopia, Tritanopia} {
+		parsed, err := ParseDeficiency(string(deficiency))
+		c.NoError(err)
+		c.Equal(deficiency, parsed)
+	}
+
+	_, err := ParseDeficiency("achromatopsia")
+	c.ErrorIs(err, ErrInvalidDeficiency)
+}
+
+func TestSimulate(t *testing.T) {
+	c := require.New(t)
+
+	red, green, blue := Color{255, 0, 0}, Color{0, 200, 0}, Color{0, 0, 255}
+
+	for _, deficiency := range []Deficiency{Protanopia, Deuteranopia, Tritanopia} {
+		// Grays carry no hue to lose
+		c.Equal(white, deficiency.Simulate(white))
+		c.Equal(black, deficiency.Simulate(black))
+	}
+
+	// Red and green are the classic confusion for red-green deficiencies
+	c.Greater(DeltaE(red, green), 50.0)
+	c.Less(DeltaE(Deuteranopia.Simulate(red), Deuteranopia.Simulate(green)), 15.0)
+	c.Less(DeltaE(Protanopia.Simulate(red), Protanopia.Simulate(green)), DeltaE(red, green)/2)
+
+	// Tritanopia keeps red and green apart but loses blue
+	c.Greater(DeltaE(Tritanopia.Simulate(red), Tritanopia.Simulate(green)), 50.0)
+	c.Greater(DeltaE(blue, Tritanopia.Simulate(blue)), 15.0)
+}
+
+func TestPaletteSimulate(t *testing.T) {
+	c := require.New(t)
+
+	nord := nordPalette(c)
+	simulated := nord.Simulate(Deuteranopia)
+
+	c.Equal(Deuteranopia.Simulate(nord.Background), simulated.Background)
+	c.Equal(Deuteranopia.Simulate(nord.Normal[1]), simulated.Normal[1])
+	c.Equal(Deuteranopia.Simulate(nord.Bright[6]), simulated.Bright[6])
+	c.Equal(Deuteranopia.Simulate(nord.MatchBackground), simulated.MatchBackground)
+	c.Equal(mustParse(c, "#bf616a"), nord.Normal[1])
+}
+
+func TestConfusions(t *testing.T) {
+	c := require.New(t)
+
+	p := &Palette{}
+	for i := range p.Normal {
+		p.Normal[i] = Color{uint8(i * 30), uint8(i * 30), uint8(i * 30)}
+		p.Bright[i] = p.Normal[i]
+	}
+	p.Normal[1] = Color{220, 40, 40}
+	p.Normal[2] = Color{90, 160, 40}
+
+	confusions := p.Confusions(Deuteranopia, Distinguishable)
+
+	found := false
+	for _, confusion := range confusions {
+		c.GreaterOrEqual(confusion.Before, Distinguishable)
+		c.Less(confusion.After, Distinguishable)
+		if confusion.First == "normal red" && confusion.Second == "normal green" {
+			found = true
+		}
+	}
+	c.True(found, "red and green are confused under deuteranopia")
+
+	for _, confusion := range p.Confusions(Tritanopia, Distinguishable) {
+		c.False(confusion.First == "normal red" && confusion.Second == "normal green")
+	}
+
+	c.Empty(p.Confusions(Deuteranopia, 0))
+}
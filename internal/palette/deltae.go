@@ -0,0 +1,89 @@
+package palette
+
+import "math"
+
+// DeltaE2000 is the CIEDE2000 color difference between two CIELAB colors.
+// Around 2.3 is a just noticeable difference.
+func DeltaE2000(lab1, lab2 Lab) float64 {
+	const pow25to7 = 6103515625.0
+
+	c1 := math.Hypot(lab1.A, lab1.B)
+	c2 := math.Hypot(lab2.A, lab2.B)
+	meanC := (c1 + c2) / 2
+	meanC7 := math.Pow(meanC, 7)
+	g := 0.5 * (1 - math.Sqrt(meanC7/(meanC7+pow25to7)))
+
+	a1, a2 := lab1.A*(1+g), lab2.A*(1+g)
+	c1, c2 = math.Hypot(a1, lab1.B), math.Hypot(a2, lab2.B)
+	h1, h2 := hueAngle(lab1.B, a1), hueAngle(lab2.B, a2)
+
+	deltaL := lab2.L - lab1.L
+	deltaC := c2 - c1
+
+	deltah := 0.0
+	if c1*c2 != 0 {
+		deltah = h2 - h1
+		if deltah > 180 {
+			deltah -= 360
+		} else if deltah < -180 {
+			deltah += 360
+		}
+	}
+	deltaH := 2 * math.Sqrt(c1*c2) * math.Sin(radians(deltah/2))
+
+	meanL := (lab1.L + lab2.L) / 2
+	meanC = (c1 + c2) / 2
+
+	meanH := h1 + h2
+	if c1*c2 != 0 {
+		switch {
+		case math.Abs(h1-h2) <= 180:
+			meanH = (h1 + h2) / 2
+		case h1+h2 < 360:
+			meanH = (h1 + h2 + 360) / 2
+		default:
+			meanH = (h1 + h2 - 360) / 2
+		}
+	}
+
+	t := 1 - 0.17*math.Cos(radians(meanH-30)) +
+		0.24*math.Cos(radians(2*meanH)) +
+		0.32*math.Cos(radians(3*meanH+6)) -
+		0.20*math.Cos(radians(4*meanH-63))
+
+	meanC7 = math.Pow(meanC, 7)
+	rc := 2 * math.Sqrt(meanC7/(meanC7+pow25to7))
+	deltaTheta := 30 * math.Exp(-math.Pow((meanH-275)/25, 2))
+	rt := -math.Sin(radians(2*deltaTheta)) * rc
+
+	l50 := (meanL - 50) * (meanL - 50)
+	sl := 1 + 0.015*l50/math.Sqrt(20+l50)
+	sc := 1 + 0.045*meanC
+	sh := 1 + 0.015*meanC*t
+
+	dl, dc, dh := deltaL/sl, deltaC/sc, deltaH/sh
+
+	return math.Sqrt(dl*dl + dc*dc + dh*dh + rt*dc*dh)
+}
+
+// DeltaE is the CIEDE2000 difference between two sRGB colors.
+func DeltaE(a, b Color) float64 {
+	return DeltaE2000(a.CIELAB(), b.CIELAB())
+}
+
+func hueAngle(b, a float64) float64 {
+	if a == 0 && b == 0 {
+		return 0
+	}
+
+	hue := math.Atan2(b, a) * 180 / math.Pi
+	if hue < 0 {
+		hue += 360
+	}
+
+	return hue
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,36 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeltaE2000(t *testing.T) {
+	c := require.New(t)
+
+	// Test data from Sharma, Wu and Dalal (2005)
+	cases := []struct {
+		first    Lab
+		second   Lab
+		expected float64
+	}{
+		{Lab{50, 2.6772, -79.7751}, Lab{50, 0, -82.7485}, 2.0425},
+		{Lab{50, 3.1571, -77.2803}, Lab{50, 0, -82.7485}, 2.8615},
+		{Lab{50, 0, 0}, Lab{50, -1, 2}, 2.3669},
+		{Lab{50, 2.49, -0.001}, Lab{50, -2.49, 0.0009}, 7.1792},
+		{Lab{50, 2.5, 0}, Lab{73, 25, -18}, 27.1492},
+		{Lab{50, 2.5, 0}, Lab{56, -27, -3}, 31.9030},
+		{Lab{60.2574, -34.0099, 36.2677}, Lab{60.4626, -34.1751, 39.4387}, 1.2644},
+		{Lab{22.7233, 20.0904, -46.6940}, Lab{23.0331, 14.9730, -42.5619}, 2.0373},
+		{Lab{2.0776, 0.0795, -1.1350}, Lab{0.9033, -0.0636, -0.5514}, 0.9082},
+	}
+
+	for _, tc := range cases {
+		c.InDelta(tc.expected, DeltaE2000(tc.first, tc.second), 1e-4)
+		c.InDelta(tc.expected, DeltaE2000(tc.second, tc.first), 1e-4)
+	}
+
+	c.Zero(DeltaE(white, white))
+	c.InDelta(100, DeltaE(black, white), 1e-3)
+}
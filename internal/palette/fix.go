@@ -0,0 +1,163 @@
+package palette
+
+import "fmt"
+
+const (
+	SpaceOKLCH Space = "oklch"
+	SpaceLab   Space = "lab"
+)
+
+// searchSteps bounds the bisections so fixes are deterministic and cheap
+const searchSteps = 32
+
+var ErrInvalidSpace = fmt.Errorf("color space must be %s or %s", SpaceOKLCH, SpaceLab)
+
+// Space is the color space contrast fixes move lightness in.
+type Space string
+
+// Adjustment records a text color changed to reach a contrast target
+// over Background.
+type Adjustment struct {
+	Pair       string
+	From       Color
+	To         Color
+	Background Color
+}
+
+func ParseSpace(value string) (Space, error) {
+	switch Space(value) {
+	case SpaceOKLCH, SpaceLab:
+		return Space(value), nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidSpace, value)
+}
+
+func (s Space) lch(c Color) LCh {
+	if s == SpaceLab {
+		return c.CIELAB().LCh()
+	}
+
+	return c.OKLab().LCh()
+}
+
+func (s Space) color(lch LCh) (Color, bool) {
+	if s == SpaceLab {
+		return FromCIELAB(lch.Lab())
+	}
+
+	return FromOKLab(lch.Lab())
+}
+
+func (s Space) maxLightness() float64 {
+	if s == SpaceLab {
+		return 100
+	}
+
+	return 1
+}
+
+// inGamut returns the color with lightness l and the hue of lch, lowering
+// the chroma as little as needed to stay inside sRGB.
+func (s Space) inGamut(lch LCh, l float64) Color {
+	lch.L = l
+	if color, ok := s.color(lch); ok {
+		return color
+	}
+
+	low, high := 0.0, lch.C
+	for i := 0; i < searchSteps; i++ {
+		lch.C = (low + high) / 2
+		if _, ok := s.color(lch); ok {
+			low = lch.C
+		} else {
+			high = lch.C
+		}
+	}
+
+	lch.C = low
+	color, _ := s.color(lch)
+
+	return color
+}
+
+// FixContrast moves the lightness of text, keeping its hue, until it
+// reaches target contrast over background. The lightness moves away from
+// the background when that can reach the target, and as little as needed.
+func (s Space) FixContrast(text, background Color, target float64) Color {
+	if Contrast(text, background) >= target {
+		return text
+	}
+
+	white, black := Color{255, 255, 255}, Color{}
+	whiteReaches, blackReaches := Contrast(white, background) >= target, Contrast(black, background) >= target
+
+	// Out of reach either way, the extreme with the most contrast is the
+	// closest to the target
+	if !whiteReaches && !blackReaches {
+		if Contrast(white, background) >= Contrast(black, background) {
+			return white
+		}
+
+		return black
+	}
+
+	lighter := text.Luminance() >= background.Luminance()
+	if lighter && !whiteReaches {
+		lighter = false
+	} else if !lighter && !blackReaches {
+		lighter = true
+	}
+
+	extreme, extremeL := black, 0.0
+	if lighter {
+		extreme, extremeL = white, s.maxLightness()
+	}
+
+	lch := s.lch(text)
+	failing, passing, fixed := lch.L, extremeL, extreme
+	for i := 0; i < searchSteps; i++ {
+		l := (failing + passing) / 2
+		candidate := s.inGamut(lch, l)
+		if Contrast(candidate, background) >= target {
+			passing, fixed = l, candidate
+		} else {
+			failing = l
+		}
+	}
+
+	return fixed
+}
+
+// FixContrast returns a copy of the palette whose failing text colors are
+// moved to reach target, along with what changed. The ANSI color that plays
+// the background role, black in dark themes and white in light ones, is
+// left alone since it isn't meant to be read over the background.
+func (p *Palette) FixContrast(target float64, space Space) (*Palette, []Adjustment) {
+	fixed := *p
+	adjustments := make([]Adjustment, 0)
+
+	backgroundSlot := "normal black"
+	if p.Variant() == Light {
+		backgroundSlot = "normal white"
+	}
+
+	for _, slot := range fixed.slots() {
+		if slot.name == backgroundSlot {
+			continue
+		}
+
+		color := space.FixContrast(*slot.text, *slot.background, target)
+		if color != *slot.text {
+			adjustments = append(adjustments, Adjustment{
+				Pair:       slot.name,
+				From:       *slot.text,
+				To:         color,
+				Background: *slot.background,
+			})
+			*slot.text = color
+		}
+	}
+
+	return &fixed, adjustments
+}
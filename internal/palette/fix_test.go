@@ -0,0 +1,141 @@
+package palette
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustParse(c *require.Assertions, hex string) Color {
+	color, err := ParseHex(hex)
+	c.NoError(err)
+
+	return color
+}
+
+func nordPalette(c *require.Assertions) *Palette {
+	p := &Palette{
+		Background: mustParse(c, "#2e3440"),
+		Foreground: mustParse(c, "#d8dee9"),
+	}
+
+	normal := []string{"#3b4252", "#bf616a", "#a3be8c", "#ebcb8b", "#81a1c1", "#b48ead", "#88c0d0", "#e5e9f0"}
+	bright := []string{"#4c566a", "#bf616a", "#a3be8c", "#ebcb8b", "#81a1c1", "#b48ead", "#8fbcbb", "#eceff4"}
+	for i := range normal {
+		p.Normal[i] = mustParse(c, normal[i])
+		p.Bright[i] = mustParse(c, bright[i])
+	}
+
+	p.Cursor, p.CursorText = p.Foreground, p.Background
+	p.SelectionBackground, p.SelectionText = p.Foreground, p.Background
+	p.MatchText, p.MatchBackground = mustParse(c, "#181818"), mustParse(c, "#ac4242")
+	p.FocusedText, p.FocusedBackground = mustParse(c, "#181818"), mustParse(c, "#f4bf75")
+
+	return p
+}
+
+func hueDistance(a, b float64) float64 {
+	d := math.Abs(a - b)
+
+	return math.Min(d, 360-d)
+}
+
+func TestParseSpace(t *testing.T) {
+	c := require.New(t)
+
+	space, err := ParseSpace("lab")
+	c.NoError(err)
+	c.Equal(SpaceLab, space)
+
+	space, err = ParseSpace("oklch")
+	c.NoError(err)
+	c.Equal(SpaceOKLCH, space)
+
+	_, err = ParseSpace("hsl")
+	c.ErrorIs(err, ErrInvalidSpace)
+}
+
+func TestSpaceFixContrast(t *testing.T) {
+	c := require.New(t)
+
+	background := mustParse(c, "#2e3440")
+	red := mustParse(c, "#bf616a")
+
+	for _, space := range []Space{SpaceOKLCH, SpaceLab} {
+		fixed := space.FixContrast(red, background, WCAGAA)
+		c.GreaterOrEqual(Contrast(fixed, background), WCAGAA)
+		c.Greater(fixed.Luminance(), red.Luminance(), "lightens away from a dark background")
+		c.Less(hueDistance(space.lch(red).H, space.lch(fixed).H), 2.0)
+
+		// Passing colors are left alone
+		c.Equal(fixed, space.FixContrast(fixed, background, WCAGAA))
+	}
+
+	// Text lighter than a mid background darkens when white can't make it
+	mid := mustParse(c, "#808080")
+	fixed := SpaceOKLCH.FixContrast(mustParse(c, "#a0a0a0"), mid, WCAGAA)
+	c.GreaterOrEqual(Contrast(fixed, mid), WCAGAA)
+	c.Less(fixed.Luminance(), mid.Luminance())
+
+	// Unreachable targets end at the most contrasting extreme
+	c.Equal(Color{}, SpaceOKLCH.FixContrast(mustParse(c, "#a0a0a0"), mid, 21))
+
+	// Whichever way the text starts
+	darker := mustParse(c, "#707070")
+	c.Equal(Color{255, 255, 255}, SpaceOKLCH.FixContrast(mustParse(c, "#909090"), darker, 21))
+	c.Equal(Color{255, 255, 255}, SpaceOKLCH.FixContrast(mustParse(c, "#505050"), darker, 21))
+}
+
+func TestPaletteFixContrast(t *testing.T) {
+	c := require.New(t)
+
+	nord := nordPalette(c)
+	fixed, adjustments := nord.FixContrast(WCAGAA, SpaceOKLCH)
+
+	c.Equal([]Adjustment{
+		{Pair: "normal red", From: mustParse(c, "#bf616a"), To: mustParse(c, "#e17f87"), Background: nord.Background},
+		{Pair: "normal magenta", From: mustParse(c, "#b48ead"), To: mustParse(c, "#b690af"), Background: nord.Background},
+		{Pair: "bright black", From: mustParse(c, "#4c566a"), To: mustParse(c, "#909cb2"), Background: nord.Background},
+		{Pair: "bright red", From: mustParse(c, "#bf616a"), To: mustParse(c, "#e17f87"), Background: nord.Background},
+		{Pair: "bright magenta", From: mustParse(c, "#b48ead"), To: mustParse(c, "#b690af"), Background: nord.Background},
+		{Pair: "search match", From: mustParse(c, "#181818"), To: mustParse(c, "#e3e3e2"), Background: nord.MatchBackground},
+	}, adjustments)
+
+	// The original palette is untouched and black keeps its background role
+	c.Equal(mustParse(c, "#bf616a"), nord.Normal[1])
+	c.Equal(nord.Normal[0], fixed.Normal[0])
+
+	for _, pair := range fixed.Pairs() {
+		if pair.Name == "normal black" {
+			continue
+		}
+		c.GreaterOrEqual(pair.Ratio(), WCAGAA, pair.Name)
+	}
+
+	again, adjustments := nord.FixContrast(WCAGAA, SpaceOKLCH)
+	c.Equal(fixed, again)
+	c.Len(adjustments, 6)
+
+	_, adjustments = fixed.FixContrast(WCAGAA, SpaceOKLCH)
+	c.Empty(adjustments)
+}
+
+func TestPaletteFixContrastLight(t *testing.T) {
+	c := require.New(t)
+
+	solarized := nordPalette(c)
+	solarized.Background = mustParse(c, "#fdf6e3")
+	solarized.Foreground = mustParse(c, "#657b83")
+	solarized.Normal[7] = mustParse(c, "#eee8d5")
+
+	fixed, adjustments := solarized.FixContrast(WCAGAA, SpaceLab)
+	c.Equal(solarized.Normal[7], fixed.Normal[7])
+	c.Equal("foreground", adjustments[0].Pair)
+	c.Less(fixed.Foreground.Luminance(), solarized.Foreground.Luminance())
+
+	for _, adjustment := range adjustments {
+		c.NotEqual("normal white", adjustment.Pair)
+		c.GreaterOrEqual(Contrast(adjustment.To, adjustment.Background), WCAGAA)
+	}
+}
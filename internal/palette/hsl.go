@@ -0,0 +1,85 @@
+package palette
+
+import "math"
+
+// HSL is a color as its hue in degrees, its saturation and its lightness,
+// both from 0 to 1, the space of CSS hsl().
+type HSL struct {
+	H float64
+	S float64
+	L float64
+}
+
+func (c Color) HSL() HSL {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	high, low := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+
+	hsl := HSL{L: (high + low) / 2}
+	chroma := high - low
+	if chroma == 0 {
+		return hsl
+	}
+
+	hsl.S = chroma / (1 - math.Abs(2*hsl.L-1))
+
+	switch high {
+	case r:
+		hsl.H = math.Mod((g-b)/chroma, 6)
+	case g:
+		hsl.H = (b-r)/chroma + 2
+	default:
+		hsl.H = (r-g)/chroma + 4
+	}
+
+	hsl.H *= 60
+	if hsl.H < 0 {
+		hsl.H += 360
+	}
+
+	return hsl
+}
+
+// Color converts back to sRGB, clamping the saturation and the lightness.
+func (hsl HSL) Color() Color {
+	s, l := math.Min(math.Max(hsl.S, 0), 1), math.Min(math.Max(hsl.L, 0), 1)
+	h := math.Mod(hsl.H, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = chroma, x
+	case h < 120:
+		r, g = x, chroma
+	case h < 180:
+		g, b = chroma, x
+	case h < 240:
+		g, b = x, chroma
+	case h < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+
+	m := l - chroma/2
+	channel := func(v float64) uint8 {
+		return uint8(math.Round((v + m) * 255))
+	}
+
+	return Color{R: channel(r), G: channel(g), B: channel(b)}
+}
+
+// Mix blends weight of other into c, channel by channel, like Sass mix().
+func (c Color) Mix(other Color, weight float64) Color {
+	weight = math.Min(math.Max(weight, 0), 1)
+	channel := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a)*(1-weight) + float64(b)*weight))
+	}
+
+	return Color{R: channel(c.R, other.R), G: channel(c.G, other.G), B: channel(c.B, other.B)}
+}
@@ -0,0 +1,37 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHSL(t *testing.T) {
+	c := require.New(t)
+
+	hsl := Color{0x2e, 0x34, 0x40}.HSL()
+	c.InDelta(220, hsl.H, 0.01)
+	c.InDelta(0.1636, hsl.S, 0.0001)
+	c.InDelta(0.2157, hsl.L, 0.0001)
+
+	c.Equal(HSL{L: 1}, white.HSL())
+	c.Equal(HSL{H: 0, S: 1, L: 0.5}, Color{255, 0, 0}.HSL())
+	c.InDelta(311.05, Color{0xb4, 0x8e, 0xad}.HSL().H, 0.01)
+
+	// Every color comes back from its HSL form
+	for _, color := range []Color{{0x2e, 0x34, 0x40}, {0xbf, 0x61, 0x6a}, {0xa3, 0xbe, 0x8c}, {0x81, 0xa1, 0xc1}, {0xb4, 0x8e, 0xad}, white, {}} {
+		c.Equal(color, color.HSL().Color())
+	}
+
+	c.Equal(white, HSL{H: 120, S: 0.5, L: 1.5}.Color())
+	c.Equal(Color{255, 0, 0}, HSL{H: 360, S: 1, L: 0.5}.Color())
+}
+
+func TestMix(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal(Color{128, 128, 128}, Color{}.Mix(white, 0.5))
+	c.Equal(Color{64, 64, 64}, Color{}.Mix(white, 0.25))
+	c.Equal(white, Color{}.Mix(white, 2))
+	c.Equal(Color{0x2e, 0x34, 0x40}, Color{0x2e, 0x34, 0x40}.Mix(white, 0))
+}
@@ -0,0 +1,153 @@
+package palette
+
+import "math"
+
+// CIE constants for converting XYZ to CIELAB under the D65 white point
+const (
+	labEpsilon = 216.0 / 24389.0
+	labKappa   = 24389.0 / 27.0
+	whiteX     = 0.95047
+	whiteY     = 1.0
+	whiteZ     = 1.08883
+)
+
+// Lab is a color in a perceptual Lab space, CIELAB or OKLab depending on
+// where it came from. CIELAB lightness goes from 0 to 100, OKLab from 0 to 1.
+type Lab struct {
+	L float64
+	A float64
+	B float64
+}
+
+// LCh is the polar form of a Lab color: lightness, chroma and hue in degrees.
+type LCh struct {
+	L float64
+	C float64
+	H float64
+}
+
+func (lab Lab) LCh() LCh {
+	hue := math.Atan2(lab.B, lab.A) * 180 / math.Pi
+	if hue < 0 {
+		hue += 360
+	}
+
+	return LCh{L: lab.L, C: math.Hypot(lab.A, lab.B), H: hue}
+}
+
+func (lch LCh) Lab() Lab {
+	radians := lch.H * math.Pi / 180
+
+	return Lab{L: lch.L, A: lch.C * math.Cos(radians), B: lch.C * math.Sin(radians)}
+}
+
+// CIELAB converts the color to CIELAB under D65.
+func (c Color) CIELAB() Lab {
+	r, g, b := c.linear()
+
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / whiteX
+	y := (0.2126729*r + 0.7151522*g + 0.0721750*b) / whiteY
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / whiteZ
+
+	fx, fy, fz := labF(x), labF(y), labF(z)
+
+	return Lab{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}
+}
+
+// FromCIELAB converts a CIELAB color back to sRGB, reporting whether it
+// fitted in the sRGB gamut before being clipped.
+func FromCIELAB(lab Lab) (Color, bool) {
+	fy := (lab.L + 16) / 116
+	fx := fy + lab.A/500
+	fz := fy - lab.B/200
+
+	x := labFInverse(fx) * whiteX
+	z := labFInverse(fz) * whiteZ
+	y := lab.L / labKappa
+	if lab.L > labKappa*labEpsilon {
+		y = fy * fy * fy
+	}
+	y *= whiteY
+
+	return fromLinear(
+		3.2404542*x-1.5371385*y-0.4985314*z,
+		-0.9692660*x+1.8760108*y+0.0415560*z,
+		0.0556434*x-0.2040259*y+1.0572252*z,
+	)
+}
+
+// OKLab converts the color to Björn Ottosson's OKLab.
+func (c Color) OKLab() Lab {
+	r, g, b := c.linear()
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return Lab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// FromOKLab converts an OKLab color back to sRGB, reporting whether it
+// fitted in the sRGB gamut before being clipped.
+func FromOKLab(lab Lab) (Color, bool) {
+	l := lab.L + 0.3963377774*lab.A + 0.2158037573*lab.B
+	m := lab.L - 0.1055613458*lab.A - 0.0638541728*lab.B
+	s := lab.L - 0.0894841775*lab.A - 1.2914855480*lab.B
+
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	return fromLinear(
+		4.0767416621*l-3.3077115913*m+0.2309699292*s,
+		-1.2684380046*l+2.6097574011*m-0.3413193965*s,
+		-0.0041960863*l-0.7034186147*m+1.7076147010*s,
+	)
+}
+
+func labF(t float64) float64 {
+	if t > labEpsilon {
+		return math.Cbrt(t)
+	}
+
+	return (labKappa*t + 16) / 116
+}
+
+func labFInverse(f float64) float64 {
+	if cube := f * f * f; cube > labEpsilon {
+		return cube
+	}
+
+	return (116*f - 16) / labKappa
+}
+
+func (c Color) linear() (float64, float64, float64) {
+	return linearize(c.R), linearize(c.G), linearize(c.B)
+}
+
+// gamutTolerance absorbs the rounding of the conversion matrices
+const gamutTolerance = 1e-6
+
+func fromLinear(r, g, b float64) (Color, bool) {
+	inGamut := true
+	channel := func(v float64) uint8 {
+		if v < -gamutTolerance || v > 1+gamutTolerance {
+			inGamut = false
+		}
+		v = math.Min(math.Max(v, 0), 1)
+
+		if v <= 0.0031308 {
+			v *= 12.92
+		} else {
+			v = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+
+		return uint8(math.Round(v * 255))
+	}
+
+	color := Color{R: channel(r), G: channel(g), B: channel(b)}
+
+	return color, inGamut
+}
@@ -0,0 +1,66 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIELAB(t *testing.T) {
+	c := require.New(t)
+
+	lab := white.CIELAB()
+	c.InDelta(100, lab.L, 1e-3)
+	c.InDelta(0, lab.A, 1e-3)
+	c.InDelta(0, lab.B, 1e-3)
+
+	lab = Color{255, 0, 0}.CIELAB()
+	c.InDelta(53.24, lab.L, 0.01)
+	c.InDelta(80.09, lab.A, 0.01)
+	c.InDelta(67.20, lab.B, 0.01)
+
+	lch := lab.LCh()
+	c.InDelta(104.55, lch.C, 0.01)
+	c.InDelta(40.0, lch.H, 0.1)
+
+	color, ok := FromCIELAB(lab)
+	c.True(ok)
+	c.Equal(Color{255, 0, 0}, color)
+
+	_, ok = FromCIELAB(Lab{L: 50, A: 120, B: -120})
+	c.False(ok)
+}
+
+func TestOKLab(t *testing.T) {
+	c := require.New(t)
+
+	lab := white.OKLab()
+	c.InDelta(1, lab.L, 1e-4)
+	c.InDelta(0, lab.A, 1e-4)
+	c.InDelta(0, lab.B, 1e-4)
+
+	lab = Color{255, 0, 0}.OKLab()
+	c.InDelta(0.62796, lab.L, 1e-4)
+	c.InDelta(0.22486, lab.A, 1e-4)
+	c.InDelta(0.12585, lab.B, 1e-4)
+
+	_, ok := FromOKLab(LCh{L: 0.9, C: 0.3, H: 150}.Lab())
+	c.False(ok)
+}
+
+func TestRoundTrip(t *testing.T) {
+	c := require.New(t)
+
+	for _, hex := range []string{"#2e3440", "#d8dee9", "#bf616a", "#a3be8c", "#000000", "#ffffff", "#5e81ac"} {
+		color, err := ParseHex(hex)
+		c.NoError(err)
+
+		back, ok := FromCIELAB(color.CIELAB())
+		c.True(ok, hex)
+		c.Equal(color, back, hex)
+
+		back, ok = FromOKLab(color.OKLab().LCh().Lab())
+		c.True(ok, hex)
+		c.Equal(color, back, hex)
+	}
+}
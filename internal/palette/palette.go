@@ -0,0 +1,123 @@
+// Package palette models the colors of a terminal theme independently of
+// the file format they were read from.
+package palette
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	Dark  Variant = "dark"
+	Light Variant = "light"
+)
+
+// darkLuminance is the background luminance under which white text
+// contrasts better than black text, the usual split between dark and light.
+const darkLuminance = 0.179
+
+// Colors Alacritty uses for search matches when a theme doesn't set them
+var (
+	DefaultMatchText         = Color{R: 0x18, G: 0x18, B: 0x18}
+	DefaultMatchBackground   = Color{R: 0xac, G: 0x42, B: 0x42}
+	DefaultFocusedText       = Color{R: 0x18, G: 0x18, B: 0x18}
+	DefaultFocusedBackground = Color{R: 0xf4, G: 0xbf, B: 0x75}
+)
+
+var ErrInvalidVariant = errors.New("variant must be dark or light")
+
+// ANSINames are the names Alacritty uses for the eight normal and bright colors.
+var ANSINames = [8]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// Variant tells whether a theme is meant for a dark or a light background.
+type Variant string
+
+// Palette holds the colors of a theme. Colors Alacritty lets a theme
+// leave out are filled with what Alacritty would draw instead.
+type Palette struct {
+	Background          Color
+	Foreground          Color
+	Normal              [8]Color
+	Bright              [8]Color
+	Cursor              Color
+	CursorText          Color
+	SelectionBackground Color
+	SelectionText       Color
+	MatchBackground     Color
+	MatchText           Color
+	FocusedBackground   Color
+	FocusedText         Color
+}
+
+// NamedColor is a palette color along with the slot it fills.
+type NamedColor struct {
+	Name  string
+	Color Color
+}
+
+func ParseVariant(value string) (Variant, error) {
+	switch Variant(value) {
+	case Dark, Light:
+		return Variant(value), nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidVariant, value)
+}
+
+// Variant classifies the palette from the luminance of its primary background.
+func (p *Palette) Variant() Variant {
+	if p.Background.Luminance() < darkLuminance {
+		return Dark
+	}
+
+	return Light
+}
+
+// ANSI lists the sixteen ANSI colors with their names, normal ones first.
+func (p *Palette) ANSI() []NamedColor {
+	colors := make([]NamedColor, 0, 16)
+	for i, name := range ANSINames {
+		colors = append(colors, NamedColor{"normal " + name, p.Normal[i]})
+	}
+
+	for i, name := range ANSINames {
+		colors = append(colors, NamedColor{"bright " + name, p.Bright[i]})
+	}
+
+	return colors
+}
+
+// colors lists the background, the foreground and the sixteen ANSI colors,
+// the colors that make a palette recognizable.
+func (p *Palette) colors() []Color {
+	colors := []Color{p.Background, p.Foreground}
+	colors = append(colors, p.Normal[:]...)
+
+	return append(colors, p.Bright[:]...)
+}
+
+// Same reports whether two palettes share their background, foreground and
+// ANSI colors, what Distance compares.
+func Same(a, b *Palette) bool {
+	first, second := a.colors(), b.colors()
+	for i := range first {
+		if first[i] != second[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Distance is the mean CIEDE2000 difference between the background,
+// foreground and ANSI colors of two palettes.
+func Distance(a, b *Palette) float64 {
+	first, second := a.colors(), b.colors()
+
+	total := 0.0
+	for i := range first {
+		total += DeltaE(first[i], second[i])
+	}
+
+	return total / float64(len(first))
+}
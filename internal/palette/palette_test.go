@@ -0,0 +1,81 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariant(t *testing.T) {
+	c := require.New(t)
+
+	variant, err := ParseVariant("dark")
+	c.NoError(err)
+	c.Equal(Dark, variant)
+
+	variant, err = ParseVariant("light")
+	c.NoError(err)
+	c.Equal(Light, variant)
+
+	_, err = ParseVariant("Night")
+	c.ErrorIs(err, ErrInvalidVariant)
+}
+
+func TestPaletteVariant(t *testing.T) {
+	c := require.New(t)
+
+	nord := &Palette{Background: Color{0x2e, 0x34, 0x40}}
+	c.Equal(Dark, nord.Variant())
+
+	solarizedLight := &Palette{Background: Color{0xfd, 0xf6, 0xe3}}
+	c.Equal(Light, solarizedLight.Variant())
+
+	gray := &Palette{Background: Color{0x80, 0x80, 0x80}}
+	c.Equal(Light, gray.Variant())
+
+	darkGray := &Palette{Background: Color{0x70, 0x70, 0x70}}
+	c.Equal(Dark, darkGray.Variant())
+}
+
+func TestANSI(t *testing.T) {
+	c := require.New(t)
+
+	p := &Palette{}
+	p.Normal[1] = Color{255, 0, 0}
+	p.Bright[7] = Color{255, 255, 255}
+
+	ansi := p.ANSI()
+	c.Len(ansi, 16)
+	c.Equal(NamedColor{"normal red", Color{255, 0, 0}}, ansi[1])
+	c.Equal(NamedColor{"bright white", Color{255, 255, 255}}, ansi[15])
+}
+
+func TestDistance(t *testing.T) {
+	c := require.New(t)
+
+	nord := nordPalette(c)
+	c.Zero(Distance(nord, nord))
+
+	warmer := *nord
+	warmer.Background = Color{0x40, 0x34, 0x2e}
+	c.InDelta(DeltaE(nord.Background, warmer.Background)/18, Distance(nord, &warmer), 1e-9)
+	c.InDelta(Distance(nord, &warmer), Distance(&warmer, nord), 1e-9)
+
+	inverted := &Palette{Background: nord.Foreground, Foreground: nord.Background, Normal: nord.Normal, Bright: nord.Bright}
+	c.Greater(Distance(nord, inverted), Distance(nord, &warmer))
+}
+
+func TestSame(t *testing.T) {
+	c := require.New(t)
+
+	nord := nordPalette(c)
+	copied := *nord
+	c.True(Same(nord, &copied))
+
+	// Only the colors Distance compares matter
+	copied.Cursor = Color{255, 0, 0}
+	c.True(Same(nord, &copied))
+
+	copied.Bright[7] = Color{255, 0, 0}
+	c.False(Same(nord, &copied))
+}
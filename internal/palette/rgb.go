@@ -0,0 +1,64 @@
+package palette
+
+import "math"
+
+// Matrices from the linear components of RGB spaces sharing the D65 white
+// point to linear sRGB, derived from the primaries of each space
+var (
+	displayP3ToSRGB = [3][3]float64{
+		{1.2249402, -0.2249402, 0},
+		{-0.0420570, 1.0420570, 0},
+		{-0.0196376, -0.0786360, 1.0982736},
+	}
+	genericRGBToSRGB = [3][3]float64{
+		{1.0252525, -0.0265475, 0.0012951},
+		{0.0193935, 0.9480280, 0.0325785},
+		{-0.0017695, -0.0014423, 1.0032119},
+	}
+)
+
+// genericGamma is the transfer function of Apple's Generic RGB.
+const genericGamma = 1.8
+
+// FromSRGB converts sRGB components between 0 and 1 to a color.
+func FromSRGB(r, g, b float64) Color {
+	channel := func(v float64) uint8 {
+		return uint8(math.Round(math.Min(math.Max(v, 0), 1) * 255))
+	}
+
+	return Color{R: channel(r), G: channel(g), B: channel(b)}
+}
+
+// FromDisplayP3 converts Display P3 components between 0 and 1 to sRGB,
+// reporting whether the color fitted in the sRGB gamut before being clipped.
+func FromDisplayP3(r, g, b float64) (Color, bool) {
+	return convertLinear(displayP3ToSRGB, srgbLinear(r), srgbLinear(g), srgbLinear(b))
+}
+
+// FromGenericRGB converts components of Apple's Generic RGB, the
+// calibrated RGB of macOS color pickers, between 0 and 1 to sRGB,
+// reporting whether the color fitted in the sRGB gamut before being clipped.
+func FromGenericRGB(r, g, b float64) (Color, bool) {
+	gamma := func(v float64) float64 {
+		return math.Pow(math.Max(v, 0), genericGamma)
+	}
+
+	return convertLinear(genericRGBToSRGB, gamma(r), gamma(g), gamma(b))
+}
+
+// srgbLinear undoes the sRGB transfer function, Display P3 uses it too.
+func srgbLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func convertLinear(m [3][3]float64, r, g, b float64) (Color, bool) {
+	return fromLinear(
+		m[0][0]*r+m[0][1]*g+m[0][2]*b,
+		m[1][0]*r+m[1][1]*g+m[1][2]*b,
+		m[2][0]*r+m[2][1]*g+m[2][2]*b,
+	)
+}
@@ -0,0 +1,37 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromRGBSpaces(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal(Color{0x2e, 0x34, 0x40}, FromSRGB(0x2e/255.0, 0x34/255.0, 0x40/255.0))
+	c.Equal(Color{255, 0, 0}, FromSRGB(1.2, 0, -0.1))
+
+	color, ok := FromDisplayP3(1, 1, 1)
+	c.True(ok)
+	c.Equal(white, color)
+
+	// Greys are the same in both spaces
+	color, ok = FromDisplayP3(0.5, 0.5, 0.5)
+	c.True(ok)
+	c.Equal(Color{128, 128, 128}, color)
+
+	// The P3 red is beyond the sRGB one
+	color, ok = FromDisplayP3(1, 0, 0)
+	c.False(ok)
+	c.Equal(Color{255, 0, 0}, color)
+
+	color, ok = FromGenericRGB(1, 1, 1)
+	c.True(ok)
+	c.Equal(white, color)
+
+	// Its 1.8 gamma makes the middle grey lighter in sRGB
+	color, ok = FromGenericRGB(0.5, 0.5, 0.5)
+	c.True(ok)
+	c.Equal(Color{146, 146, 146}, color)
+}
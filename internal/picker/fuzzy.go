@@ -0,0 +1,116 @@
+package picker
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fuzzy match scores
+const (
+	scoreMatch       = 16
+	scoreBoundary    = 8
+	scoreConsecutive = 4
+	penaltyGap       = 1
+)
+
+// fuzzyMatch reports whether the runes of pattern appear in order in text,
+// ignoring case. The score favours runes starting words and runes next to
+// each other, positions are the indexes of the matched runes of text.
+func fuzzyMatch(pattern, text []rune) (int, []int, bool) {
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// Find where the first occurrence ends, then walk back from there to
+	// the latest start so "mocha" in "monokai/mocha" matches the word.
+	p, end := 0, -1
+	for i, r := range lower {
+		if r == unicode.ToLower(pattern[p]) {
+			p++
+			if p == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	p, start := len(pattern)-1, end
+	for i := end; i >= 0; i-- {
+		if lower[i] == unicode.ToLower(pattern[p]) {
+			p--
+			if p < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	positions := make([]int, 0, len(pattern))
+	score := 0
+	p = 0
+	for i := start; i <= end && p < len(pattern); i++ {
+		if lower[i] != unicode.ToLower(pattern[p]) {
+			continue
+		}
+
+		score += scoreMatch
+		if isBoundary(text, i) {
+			score += scoreBoundary
+		}
+
+		if len(positions) > 0 {
+			previous := positions[len(positions)-1]
+			if previous == i-1 {
+				score += scoreConsecutive
+			} else {
+				score -= penaltyGap * (i - previous - 1)
+			}
+		}
+
+		positions = append(positions, i)
+		p++
+	}
+
+	return score, positions, true
+}
+
+// isBoundary reports whether the rune at i starts a word of text.
+func isBoundary(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+
+	previous := text[i-1]
+	if strings.ContainsRune(" /._-#", previous) {
+		return true
+	}
+
+	return unicode.IsLower(previous) && unicode.IsUpper(text[i])
+}
+
+// matchQuery matches every space separated word of query against text,
+// summing their scores.
+func matchQuery(query string, text []rune) (int, []int, bool) {
+	score := 0
+	positions := make([]int, 0)
+	for _, word := range strings.Fields(query) {
+		wordScore, wordPositions, ok := fuzzyMatch([]rune(word), text)
+		if !ok {
+			return 0, nil, false
+		}
+
+		score += wordScore
+		positions = append(positions, wordPositions...)
+	}
+
+	return score, positions, true
+}
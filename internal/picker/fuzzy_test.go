@@ -0,0 +1,51 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	c := require.New(t)
+
+	_, positions, ok := fuzzyMatch([]rune("mch"), []rune("Mocha.toml"))
+	c.True(ok)
+	c.Equal([]int{0, 2, 3}, positions)
+
+	_, _, ok = fuzzyMatch([]rune("mcx"), []rune("Mocha.toml"))
+	c.False(ok)
+
+	// The match ends as early as possible and starts as late as possible
+	_, positions, ok = fuzzyMatch([]rune("mocha"), []rune("monokai/mocha.toml"))
+	c.True(ok)
+	c.Equal([]int{8, 9, 10, 11, 12}, positions)
+
+	// Word starts and consecutive runes score higher
+	wordStart, _, _ := fuzzyMatch([]rune("night"), []rune("tokyo-night.toml"))
+	scattered, _, _ := fuzzyMatch([]rune("night"), []rune("nord-light.toml"))
+	c.Greater(wordStart, scattered)
+
+	camelCase, _, _ := fuzzyMatch([]rune("gb"), []rune("GruvboxDarkBlue"))
+	_, _, ok = fuzzyMatch([]rune("gb"), []rune("GruvboxDarkBlue"))
+	c.True(ok)
+	c.Positive(camelCase)
+}
+
+func TestMatchQuery(t *testing.T) {
+	c := require.New(t)
+
+	text := []rune("catppuccin/Mocha.toml  dark  #work")
+
+	_, positions, ok := matchQuery("cat dark", text)
+	c.True(ok)
+	c.Equal([]int{0, 1, 2, 23, 24, 25, 26}, positions)
+
+	_, _, ok = matchQuery("cat light", text)
+	c.False(ok)
+
+	score, positions, ok := matchQuery("  ", text)
+	c.True(ok)
+	c.Zero(score)
+	c.Empty(positions)
+}
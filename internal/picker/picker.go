@@ -0,0 +1,428 @@
+// Package picker is the interactive theme selector: a fuzzy searchable
+// list with the favorite and recent themes on top.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"atomicgo.dev/keyboard"
+	"atomicgo.dev/keyboard/keys"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/pterm/pterm"
+)
+
+const defaultMaxHeight = 10
+
+var (
+	// ErrNoOptions keeps the message of pterm's select, which the picker replaced.
+	ErrNoOptions = errors.New("no options provided")
+	ErrCancelled = errors.New("selection cancelled")
+)
+
+// Section groups the items of the picker, listed in this order.
+type Section int
+
+const (
+	SectionFavorites Section = iota
+	SectionRecent
+	SectionThemes
+)
+
+var sectionTitles = map[Section]string{
+	SectionFavorites: "Favorites",
+	SectionRecent:    "Recent",
+	SectionThemes:    "Themes",
+}
+
+var (
+	highlightStyle  = pterm.NewStyle(pterm.FgLightCyan, pterm.Bold)
+	annotationStyle = pterm.NewStyle(pterm.FgGray)
+	sectionStyle    = pterm.NewStyle(pterm.FgLightMagenta, pterm.Bold)
+	categoryStyle   = pterm.NewStyle(pterm.FgMagenta)
+	selectorStyle   = pterm.NewStyle(pterm.FgLightCyan)
+)
+
+// Item is a theme offered by the picker. Its name, which holds the
+// category, variant and tags are all searched.
+type Item struct {
+	Name    string
+	Variant palette.Variant
+	Tags    []string
+	Section Section
+}
+
+// category is the directory of a theme listed in the themes section,
+// empty for the top-level ones.
+func (item Item) category() string {
+	if item.Section != SectionThemes {
+		return ""
+	}
+
+	category := path.Dir(item.Name)
+	if category == "." {
+		return ""
+	}
+
+	return category
+}
+
+// text is the line showing the item, the one queries are matched against.
+func (item Item) text() []rune {
+	text := item.Name
+	if item.Variant != "" {
+		text += "  " + string(item.Variant)
+	}
+
+	if len(item.Tags) > 0 {
+		text += "  #" + strings.Join(item.Tags, " #")
+	}
+
+	return []rune(text)
+}
+
+type match struct {
+	item      Item
+	text      []rune
+	score     int
+	positions []int
+}
+
+// Picker keeps the state of the selector. It's driven by HandleKey so
+// tests can feed it keys without a terminal.
+type Picker struct {
+	Title     string
+	MaxHeight int
+
+	items     []Item
+	query     []rune
+	matches   []match
+	selected  int
+	offset    int
+	done      bool
+	cancelled bool
+}
+
+func New(title string, items []Item) *Picker {
+	p := &Picker{Title: title, MaxHeight: defaultMaxHeight, items: items}
+	p.search()
+
+	return p
+}
+
+// search matches the items against the query, keeping the sections in
+// order. The themes stay grouped by category, the groups sorted by their
+// best match and each sorted by score.
+func (p *Picker) search() {
+	query := string(p.query)
+
+	categories := make(map[string]int)
+	for _, item := range p.items {
+		if _, ok := categories[item.category()]; !ok {
+			categories[item.category()] = len(categories)
+		}
+	}
+
+	p.matches = p.matches[:0]
+	for _, item := range p.items {
+		text := item.text()
+		score, positions, ok := matchQuery(query, text)
+		if !ok {
+			continue
+		}
+
+		p.matches = append(p.matches, match{item, text, score, positions})
+	}
+
+	// A category is as good a match as its best theme
+	best := make(map[string]int)
+	for _, m := range p.matches {
+		category := m.item.category()
+		if score, ok := best[category]; !ok || m.score > score {
+			best[category] = m.score
+		}
+	}
+
+	sort.SliceStable(p.matches, func(i, j int) bool {
+		first, second := p.matches[i].item, p.matches[j].item
+		if first.Section != second.Section {
+			return first.Section < second.Section
+		}
+
+		if first.category() != second.category() {
+			if best[first.category()] != best[second.category()] {
+				return best[first.category()] > best[second.category()]
+			}
+
+			return categories[first.category()] < categories[second.category()]
+		}
+
+		return p.matches[i].score > p.matches[j].score
+	})
+
+	p.selected, p.offset = 0, 0
+}
+
+// HandleKey updates the picker with a key press and reports whether the
+// selection is over.
+func (p *Picker) HandleKey(key keys.Key) bool {
+	if p.done || p.cancelled {
+		return true
+	}
+
+	switch key.Code {
+	case keys.RuneKey:
+		p.query = append(p.query, key.Runes...)
+		p.search()
+	case keys.Space:
+		p.query = append(p.query, ' ')
+		p.search()
+	case keys.Backspace:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.search()
+		}
+	case keys.CtrlU:
+		p.query = p.query[:0]
+		p.search()
+	case keys.Up, keys.CtrlP:
+		p.move(-1)
+	case keys.Down, keys.CtrlN, keys.Tab:
+		p.move(1)
+	case keys.PgUp:
+		p.move(-p.height())
+	case keys.PgDown:
+		p.move(p.height())
+	case keys.CtrlC, keys.Escape:
+		p.cancelled = true
+		return true
+	case keys.Enter:
+		if len(p.matches) == 0 {
+			return false
+		}
+
+		p.done = true
+		return true
+	}
+
+	return false
+}
+
+// move moves the selection by delta, wrapping around the ends like
+// pterm's select did.
+func (p *Picker) move(delta int) {
+	if len(p.matches) == 0 {
+		return
+	}
+
+	switch next := p.selected + delta; {
+	case next < 0 && p.selected > 0:
+		p.selected = 0
+	case next < 0:
+		p.selected = len(p.matches) - 1
+	case next >= len(p.matches) && p.selected < len(p.matches)-1:
+		p.selected = len(p.matches) - 1
+	case next >= len(p.matches):
+		p.selected = 0
+	default:
+		p.selected = next
+	}
+
+	if p.selected < p.offset {
+		p.offset = p.selected
+	}
+
+	if p.selected >= p.offset+p.height() {
+		p.offset = p.selected - p.height() + 1
+	}
+}
+
+func (p *Picker) height() int {
+	if p.MaxHeight <= 0 {
+		return defaultMaxHeight
+	}
+
+	return p.MaxHeight
+}
+
+// Selected returns the highlighted item, false when nothing matches.
+func (p *Picker) Selected() (Item, bool) {
+	if len(p.matches) == 0 {
+		return Item{}, false
+	}
+
+	return p.matches[p.selected].item, true
+}
+
+// Query returns what was typed to search.
+func (p *Picker) Query() string {
+	return string(p.query)
+}
+
+// Count returns how many items match the query and how many there are.
+func (p *Picker) Count() (int, int) {
+	return len(p.matches), len(p.items)
+}
+
+// SetItems replaces the items, keeping the query and the selected theme.
+func (p *Picker) SetItems(items []Item) {
+	selected, ok := p.Selected()
+
+	p.items = items
+	p.search()
+
+	if !ok {
+		return
+	}
+
+	for i, m := range p.matches {
+		if m.item.Name == selected.Name {
+			p.selected = i
+			p.move(0)
+			break
+		}
+	}
+}
+
+// Lines renders the visible items with their section titles and the
+// headers of the categories, cut to width runes when it's positive.
+func (p *Picker) Lines(width int) []string {
+	lines := make([]string, 0, p.height())
+
+	// Section titles are only worth showing when there are favorites or recents
+	titles := false
+	for _, item := range p.items {
+		titles = titles || item.Section != SectionThemes
+	}
+
+	end := p.offset + p.height()
+	if end > len(p.matches) {
+		end = len(p.matches)
+	}
+
+	for i := p.offset; i < end; i++ {
+		m := p.matches[i]
+		first := i == p.offset
+		if titles && (first || p.matches[i-1].item.Section != m.item.Section) {
+			lines = append(lines, sectionStyle.Sprint(sectionTitles[m.item.Section]))
+		}
+
+		category := m.item.category()
+		if category != "" && (first || p.matches[i-1].item.category() != category) {
+			lines = append(lines, categoryStyle.Sprint(category+"/"))
+		}
+
+		selector := " "
+		if i == p.selected {
+			selector = selectorStyle.Sprint(">")
+		}
+
+		if width > 2 && len(m.text) > width-2 {
+			m.text = m.text[:width-2]
+		}
+
+		lines = append(lines, selector+" "+highlight(m, len([]rune(m.item.Name))))
+	}
+
+	return lines
+}
+
+// View renders the picker.
+func (p *Picker) View() string {
+	if p.done {
+		item, _ := p.Selected()
+		return fmt.Sprintf("%s: %s\n", p.Title, highlightStyle.Sprint(item.Name))
+	}
+
+	var view strings.Builder
+	fmt.Fprintf(&view, "%s %s: %s\n", p.Title, annotationStyle.Sprint("[type to search]"), string(p.query))
+
+	for _, line := range p.Lines(0) {
+		fmt.Fprintf(&view, "%s\n", line)
+	}
+
+	fmt.Fprintf(&view, "%s\n", annotationStyle.Sprintf("%d/%d", len(p.matches), len(p.items)))
+
+	return view.String()
+}
+
+// highlight styles the matched runes of m, and the annotations after its
+// name dimmed.
+func highlight(m match, nameLength int) string {
+	matched := make(map[int]bool, len(m.positions))
+	for _, position := range m.positions {
+		matched[position] = true
+	}
+
+	style := func(i int) *pterm.Style {
+		switch {
+		case matched[i]:
+			return highlightStyle
+		case i >= nameLength:
+			return annotationStyle
+		default:
+			return nil
+		}
+	}
+
+	// Runs of runes sharing a style are printed at once
+	var text strings.Builder
+	start := 0
+	for i := 1; i <= len(m.text); i++ {
+		if i < len(m.text) && style(i) == style(start) {
+			continue
+		}
+
+		run := string(m.text[start:i])
+		if s := style(start); s != nil {
+			run = s.Sprint(run)
+		}
+
+		text.WriteString(run)
+		start = i
+	}
+
+	return text.String()
+}
+
+// Run shows the picker until a theme is selected, listening to the
+// keyboard like pterm does so keyboard.SimulateKeyPress drives it in tests.
+func (p *Picker) Run() (Item, error) {
+	if len(p.items) == 0 {
+		return Item{}, ErrNoOptions
+	}
+
+	area, err := pterm.DefaultArea.Start(p.View())
+	if err != nil {
+		return Item{}, fmt.Errorf("could not start area: %w", err)
+	}
+
+	defer area.Stop()
+
+	// keyboard calls back from its reader and from the simulated keys
+	var mu sync.Mutex
+	err = keyboard.Listen(func(key keys.Key) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		stop := p.HandleKey(key)
+		area.Update(p.View())
+
+		return stop, nil
+	})
+	if err != nil {
+		return Item{}, fmt.Errorf("failed to start keyboard listener: %w", err)
+	}
+
+	if p.cancelled {
+		return Item{}, ErrCancelled
+	}
+
+	item, _ := p.Selected()
+
+	return item, nil
+}
@@ -0,0 +1,163 @@
+package picker
+
+import (
+	"testing"
+
+	"atomicgo.dev/keyboard"
+	"atomicgo.dev/keyboard/keys"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/require"
+)
+
+var testItems = []Item{
+	{Name: "Dracula.toml", Variant: palette.Dark, Section: SectionFavorites},
+	{Name: "Nord.toml", Variant: palette.Dark, Section: SectionRecent},
+	{Name: "Afterglow.toml", Variant: palette.Dark, Section: SectionThemes},
+	{Name: "Solarized Light.toml", Variant: palette.Light, Tags: []string{"presentation"}, Section: SectionThemes},
+	{Name: "catppuccin/Latte.toml", Variant: palette.Light, Section: SectionThemes},
+	{Name: "catppuccin/Mocha.toml", Variant: palette.Dark, Tags: []string{"work"}, Section: SectionThemes},
+}
+
+func names(p *Picker) []string {
+	names := make([]string, 0, len(p.matches))
+	for _, m := range p.matches {
+		names = append(names, m.item.Name)
+	}
+
+	return names
+}
+
+func press(p *Picker, input string) {
+	for _, r := range input {
+		p.HandleKey(keys.Key{Code: keys.RuneKey, Runes: []rune{r}})
+	}
+}
+
+func TestPickerSearch(t *testing.T) {
+	c := require.New(t)
+
+	p := New("Select a theme", testItems)
+	c.Len(names(p), len(testItems))
+
+	press(p, "catp")
+	c.Equal([]string{"catppuccin/Latte.toml", "catppuccin/Mocha.toml"}, names(p))
+
+	p.HandleKey(keys.Key{Code: keys.Space})
+	press(p, "dark")
+	c.Equal([]string{"catppuccin/Mocha.toml"}, names(p))
+
+	// Tags are searched too
+	p.HandleKey(keys.Key{Code: keys.CtrlU})
+	press(p, "#pres")
+	c.Equal([]string{"Solarized Light.toml"}, names(p))
+
+	// While searching the categories stay grouped, the best one first
+	p.HandleKey(keys.Key{Code: keys.CtrlU})
+	press(p, "latte")
+	c.Equal([]string{"catppuccin/Latte.toml", "Solarized Light.toml"}, names(p))
+
+	// Favorites and recents stay on top of better matches
+	p.HandleKey(keys.Key{Code: keys.CtrlU})
+	press(p, "o")
+	c.Equal([]string{"Dracula.toml", "Nord.toml"}, names(p)[:2])
+
+	for i := 0; i < 10; i++ {
+		p.HandleKey(keys.Key{Code: keys.Backspace})
+	}
+	c.Len(names(p), len(testItems))
+
+	press(p, "zzz")
+	_, ok := p.Selected()
+	c.False(ok)
+	c.False(p.HandleKey(keys.Key{Code: keys.Enter}))
+}
+
+func TestPickerMove(t *testing.T) {
+	c := require.New(t)
+
+	p := New("Select a theme", testItems)
+	p.MaxHeight = 3
+
+	p.HandleKey(keys.Key{Code: keys.Down})
+	p.HandleKey(keys.Key{Code: keys.Down})
+	p.HandleKey(keys.Key{Code: keys.Down})
+	item, ok := p.Selected()
+	c.True(ok)
+	c.Equal("Solarized Light.toml", item.Name)
+	c.Equal(1, p.offset)
+
+	// Up from the top wraps to the bottom
+	p.HandleKey(keys.Key{Code: keys.PgUp})
+	p.HandleKey(keys.Key{Code: keys.Up})
+	item, _ = p.Selected()
+	c.Equal("catppuccin/Mocha.toml", item.Name)
+	c.Equal(3, p.offset)
+
+	p.HandleKey(keys.Key{Code: keys.Down})
+	item, _ = p.Selected()
+	c.Equal("Dracula.toml", item.Name)
+	c.Equal(0, p.offset)
+
+	c.True(p.HandleKey(keys.Key{Code: keys.Enter}))
+}
+
+func TestPickerView(t *testing.T) {
+	c := require.New(t)
+
+	p := New("Select a theme", testItems)
+	press(p, "moc")
+
+	view := pterm.RemoveColorFromString(p.View())
+	c.Equal("Select a theme [type to search]: moc\n"+
+		"Themes\n"+
+		"catppuccin/\n"+
+		"> catppuccin/Mocha.toml  dark  #work\n"+
+		"1/6\n", view)
+
+	// Matched runes are highlighted
+	c.Contains(p.View(), highlightStyle.Sprint("Moc"))
+
+	// Each category has its header, the top-level themes come first
+	p = New("Select a theme", testItems[2:])
+	view = pterm.RemoveColorFromString(p.View())
+	c.Equal("Select a theme [type to search]: \n"+
+		"> Afterglow.toml  dark\n"+
+		"  Solarized Light.toml  light  #presentation\n"+
+		"catppuccin/\n"+
+		"  catppuccin/Latte.toml  light\n"+
+		"  catppuccin/Mocha.toml  dark  #work\n"+
+		"4/4\n", view)
+
+	// Without favorites nor recents there are no section titles
+	p = New("Select a theme", testItems[2:4])
+	view = pterm.RemoveColorFromString(p.View())
+	c.NotContains(view, "Themes")
+
+	p.HandleKey(keys.Key{Code: keys.Enter})
+	c.Equal("Select a theme: Afterglow.toml\n", pterm.RemoveColorFromString(p.View()))
+}
+
+func TestPickerRun(t *testing.T) {
+	c := require.New(t)
+
+	_, err := New("Select a theme", nil).Run()
+	c.ErrorIs(err, ErrNoOptions)
+
+	go func() {
+		keyboard.SimulateKeyPress("latte")
+		keyboard.SimulateKeyPress(keys.Enter)
+	}()
+
+	item, err := New("Select a theme", testItems).Run()
+	c.NoError(err)
+	c.Equal("catppuccin/Latte.toml", item.Name)
+
+	go func() {
+		keyboard.SimulateKeyPress(keys.Down)
+		keyboard.SimulateKeyPress(keys.Escape)
+	}()
+
+	_, err = New("Select a theme", testItems).Run()
+	c.ErrorIs(err, ErrCancelled)
+}
@@ -0,0 +1,220 @@
+// Package render fills text/template files with the palette of a theme,
+// for the companions altie knows and the templates users configure.
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// Funcs are the helpers every template can call. Colors are written with
+// hex, rgb, hsl, sgr or alpha, and derived with lighten, darken and mix.
+var Funcs = template.FuncMap{
+	"hex":     hex,
+	"rgb":     rgb,
+	"hsl":     hsl,
+	"sgr":     sgr,
+	"alpha":   alpha,
+	"lighten": lighten,
+	"darken":  darken,
+	"mix":     mix,
+}
+
+// Data is what the templates are rendered with, the palette fields and
+// methods are reachable directly, as in {{.Background.Hex}}.
+type Data struct {
+	Name    string
+	Variant palette.Variant
+	*palette.Palette
+	// Colors names every color of the palette, the ANSI ones as black
+	// to white and bright_black to bright_white
+	Colors map[string]palette.Color
+}
+
+// File is a template along with the file it renders into, Name tells
+// which one failed.
+type File struct {
+	Name     string
+	Template *template.Template
+	Path     string
+}
+
+// Rendered is the content of a file waiting to be written, or a file to
+// remove when Remove is set.
+type Rendered struct {
+	Path    string
+	Content []byte
+	Remove  bool
+}
+
+func NewData(name string, p *palette.Palette) Data {
+	colors := map[string]palette.Color{
+		"background":           p.Background,
+		"foreground":           p.Foreground,
+		"cursor":               p.Cursor,
+		"cursor_text":          p.CursorText,
+		"selection_background": p.SelectionBackground,
+		"selection_text":       p.SelectionText,
+		"match_background":     p.MatchBackground,
+		"match_text":           p.MatchText,
+		"focused_background":   p.FocusedBackground,
+		"focused_text":         p.FocusedText,
+	}
+
+	for i, ansi := range palette.ANSINames {
+		colors[ansi] = p.Normal[i]
+		colors["bright_"+ansi] = p.Bright[i]
+	}
+
+	return Data{Name: name, Variant: p.Variant(), Palette: p, Colors: colors}
+}
+
+func hex(c palette.Color) string {
+	return c.Hex()
+}
+
+// rgb writes a color as CSS rgb().
+func rgb(c palette.Color) string {
+	return fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+}
+
+// hsl writes a color as CSS hsl(), rounded to whole degrees and percents.
+func hsl(c palette.Color) string {
+	value := c.HSL()
+
+	return fmt.Sprintf("hsl(%.0f, %.0f%%, %.0f%%)", value.H, value.S*100, value.L*100)
+}
+
+// sgr writes a color as the "r;g;b" of a 24-bit escape sequence, for
+// LS_COLORS and the like.
+func sgr(c palette.Color) string {
+	return fmt.Sprintf("%d;%d;%d", c.R, c.G, c.B)
+}
+
+// alpha writes a color as "#rrggbbaa" with opacity from 0 to 1.
+func alpha(c palette.Color, opacity float64) string {
+	opacity = math.Min(math.Max(opacity, 0), 1)
+
+	return fmt.Sprintf("%s%02x", c.Hex(), uint8(math.Round(opacity*255)))
+}
+
+// lighten raises the HSL lightness of a color by amount, from 0 to 1.
+func lighten(c palette.Color, amount float64) palette.Color {
+	value := c.HSL()
+	value.L += amount
+
+	return value.Color()
+}
+
+func darken(c palette.Color, amount float64) palette.Color {
+	return lighten(c, -amount)
+}
+
+// mix blends weight of b, from 0 to 1, into a.
+func mix(a, b palette.Color, weight float64) palette.Color {
+	return a.Mix(b, weight)
+}
+
+// ParseFile reads a template, a missing key of a map like .Colors is an
+// error rather than an empty string.
+func ParseFile(path string) (*template.Template, error) {
+	return template.New(filepath.Base(path)).Funcs(Funcs).Option("missingkey=error").ParseFiles(path)
+}
+
+// Render executes every file with data in memory, so a failing template
+// is found before any file is written.
+func Render(files []File, data Data) ([]Rendered, error) {
+	rendered := make([]Rendered, 0, len(files))
+	for _, file := range files {
+		var content bytes.Buffer
+		err := file.Template.Execute(&content, data)
+		if err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", file.Name, err)
+		}
+
+		rendered = append(rendered, Rendered{Path: file.Path, Content: content.Bytes()})
+	}
+
+	return rendered, nil
+}
+
+// Snapshot reads what the rendered files are about to replace, Save of
+// the snapshot puts it back and removes the files that didn't exist.
+func Snapshot(rendered []Rendered) ([]Rendered, error) {
+	previous := make([]Rendered, 0, len(rendered))
+	for _, file := range rendered {
+		content, err := os.ReadFile(file.Path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			previous = append(previous, Rendered{Path: file.Path, Remove: true})
+		case err != nil:
+			return nil, err
+		default:
+			previous = append(previous, Rendered{Path: file.Path, Content: content})
+		}
+	}
+
+	return previous, nil
+}
+
+// Save writes the rendered files, creating their directories. Each file
+// is written aside and renamed over the previous one, which is either
+// fully replaced or left as it was.
+func Save(rendered []Rendered) error {
+	for _, file := range rendered {
+		if file.Remove {
+			err := os.Remove(file.Path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+
+			continue
+		}
+
+		err := os.MkdirAll(filepath.Dir(file.Path), os.ModePerm)
+		if err != nil {
+			return err
+		}
+
+		err = replaceFile(file.Path, file.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func replaceFile(path string, content []byte) error {
+	temporary, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(temporary.Name())
+
+	_, err = temporary.Write(content)
+	if err != nil {
+		temporary.Close()
+		return err
+	}
+
+	err = temporary.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(temporary.Name(), 0o644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temporary.Name(), path)
+}
@@ -0,0 +1,110 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/themes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncs(t *testing.T) {
+	c := require.New(t)
+
+	nord := palette.Color{R: 0x2e, G: 0x34, B: 0x40}
+	white := palette.Color{R: 255, G: 255, B: 255}
+
+	c.Equal("#2e3440", hex(nord))
+	c.Equal("rgb(46, 52, 64)", rgb(nord))
+	c.Equal("hsl(220, 16%, 22%)", hsl(nord))
+	c.Equal("46;52;64", sgr(nord))
+	c.Equal("#2e344080", alpha(nord, 0.5))
+	c.Equal("#2e3440ff", alpha(nord, 3))
+	c.Equal(white, lighten(nord, 1))
+	c.Equal(palette.Color{}, darken(nord, 1))
+	c.Equal(palette.Color{R: 0x43, G: 0x4c, B: 0x5e}, lighten(nord, 0.1))
+	c.Equal(nord, darken(lighten(nord, 0.1), 0.1))
+	c.Equal(palette.Color{R: 151, G: 154, B: 160}, mix(nord, white, 0.5))
+}
+
+func TestRender(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themePalette, err := themes.LoadPalette(filepath.Join("..", "..", "themes", "Nord.toml"))
+	c.NoError(err)
+
+	data := NewData("Nord", themePalette)
+	c.Equal(themePalette.Bright[1], data.Colors["bright_red"])
+	c.Equal(themePalette.SelectionBackground, data.Colors["selection_background"])
+
+	source := filepath.Join(tmpDir, "theme.css.tmpl")
+	c.NoError(os.WriteFile(source, []byte(`/* {{.Name}}, {{.Variant}} */
+:root {
+  --bg: {{hex .Background}};
+  --surface: {{lighten .Background 0.05 | hex}};
+  --red: {{rgb .Colors.red}};
+  --blue: {{hsl (index .Normal 4)}};
+  --selection: {{alpha .SelectionBackground 0.25}};
+  --border: {{mix .Background .Foreground 0.2 | hex}};
+}
+`), 0o644))
+
+	parsed, err := ParseFile(source)
+	c.NoError(err)
+
+	target := filepath.Join(tmpDir, "out", "theme.css")
+	rendered, err := Render([]File{{Name: source, Template: parsed, Path: target}}, data)
+	c.NoError(err)
+	c.NoError(Save(rendered))
+
+	content, err := os.ReadFile(target)
+	c.NoError(err)
+	c.Equal(`/* Nord, dark */
+:root {
+  --bg: #2e3440;
+  --surface: #39404f;
+  --red: rgb(191, 97, 106);
+  --blue: hsl(210, 34%, 63%);
+  --selection: #d8dee940;
+  --border: #505662;
+}
+`, string(content))
+
+	entries, err := os.ReadDir(filepath.Dir(target))
+	c.NoError(err)
+	c.Len(entries, 1)
+
+	// A snapshot puts back the previous content and removes the new files
+	created := filepath.Join(tmpDir, "out", "created.css")
+	snapshot, err := Snapshot([]Rendered{{Path: target}, {Path: created}})
+	c.NoError(err)
+	c.NoError(Save([]Rendered{{Path: target, Content: []byte("changed")}, {Path: created, Content: []byte("new")}}))
+	c.NoError(Save(snapshot))
+
+	restored, err := os.ReadFile(target)
+	c.NoError(err)
+	c.Equal(content, restored)
+	c.NoFileExists(created)
+
+	// A missing color is an error rather than an empty value
+	c.NoError(os.WriteFile(source, []byte("{{hex .Colors.purple}}"), 0o644))
+	parsed, err = ParseFile(source)
+	c.NoError(err)
+
+	_, err = Render([]File{
+		{Name: "first", Template: template.Must(template.New("first").Parse("{{.Name}}")), Path: target},
+		{Name: source, Template: parsed, Path: target},
+	}, data)
+	c.ErrorContains(err, "rendering "+source+": template: theme.css.tmpl:1:13: executing \"theme.css.tmpl\" at <.Colors.purple>: map has no entry for key \"purple\"")
+
+	_, err = ParseFile(filepath.Join(tmpDir, "missing.tmpl"))
+	c.ErrorIs(err, os.ErrNotExist)
+}
@@ -0,0 +1,169 @@
+// Package schedule decides whether the light or the dark theme is due,
+// at fixed times of the day or at sunrise and sunset.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// Modes of a schedule
+const (
+	ModeFixed = "fixed"
+	ModeSolar = "solar"
+)
+
+var (
+	ErrNoSchedule         = errors.New("no schedule configured, see altie schedule")
+	ErrInvalidMode        = errors.New("schedule mode must be fixed or solar")
+	ErrInvalidTime        = errors.New("schedule times must be written as HH:MM")
+	ErrInvalidCoordinates = errors.New("latitude must be within ±90 and longitude within ±180")
+	ErrMissingThemes      = errors.New("schedule needs a light and a dark theme")
+)
+
+// Schedule is a validated schedule of altie.conf.
+type Schedule struct {
+	Mode       string
+	LightTheme string
+	DarkTheme  string
+	Latitude   float64
+	Longitude  float64
+
+	// Minutes after midnight of the fixed times
+	lightAt int
+	darkAt  int
+}
+
+// parseClock reads "HH:MM" into minutes after midnight.
+func parseClock(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTime, clock)
+	}
+
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// Parse validates the schedule configured in altie.conf.
+func Parse(cfg config.Schedule) (*Schedule, error) {
+	if cfg.Mode == "" {
+		return nil, ErrNoSchedule
+	}
+
+	if cfg.LightTheme == "" || cfg.DarkTheme == "" {
+		return nil, ErrMissingThemes
+	}
+
+	s := &Schedule{
+		Mode:       cfg.Mode,
+		LightTheme: cfg.LightTheme,
+		DarkTheme:  cfg.DarkTheme,
+		Latitude:   cfg.Latitude,
+		Longitude:  cfg.Longitude,
+	}
+
+	var err error
+	switch cfg.Mode {
+	case ModeFixed:
+		s.lightAt, err = parseClock(cfg.LightAt)
+		if err != nil {
+			return nil, err
+		}
+
+		s.darkAt, err = parseClock(cfg.DarkAt)
+		if err != nil {
+			return nil, err
+		}
+	case ModeSolar:
+		if cfg.Latitude < -90 || cfg.Latitude > 90 || cfg.Longitude < -180 || cfg.Longitude > 180 {
+			return nil, ErrInvalidCoordinates
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMode, cfg.Mode)
+	}
+
+	return s, nil
+}
+
+// Theme returns the theme of variant.
+func (s *Schedule) Theme(variant palette.Variant) string {
+	if variant == palette.Light {
+		return s.LightTheme
+	}
+
+	return s.DarkTheme
+}
+
+// Transitions returns when the light and the dark theme are due the day
+// of date. Around the poles, when the sun doesn't rise or set, only the
+// variant of the whole day is known and both times are zero.
+func (s *Schedule) Transitions(date time.Time) (time.Time, time.Time, palette.Variant) {
+	if s.Mode == ModeFixed {
+		year, month, day := date.Date()
+		at := func(minutes int) time.Time {
+			return time.Date(year, month, day, minutes/60, minutes%60, 0, 0, date.Location())
+		}
+
+		return at(s.lightAt), at(s.darkAt), ""
+	}
+
+	sunrise, sunset, err := SunTimes(date, s.Latitude, s.Longitude)
+	switch {
+	case errors.Is(err, ErrPolarDay):
+		return time.Time{}, time.Time{}, palette.Light
+	case errors.Is(err, ErrPolarNight):
+		return time.Time{}, time.Time{}, palette.Dark
+	}
+
+	return sunrise, sunset, ""
+}
+
+// At returns the variant due at now and when it changes next.
+func (s *Schedule) At(now time.Time) (palette.Variant, time.Time) {
+	lightAt, darkAt, allDay := s.Transitions(now)
+	if allDay != "" {
+		return allDay, s.nextDay(now)
+	}
+
+	// The light theme is due from lightAt to darkAt, past midnight when
+	// lightAt comes later in the day
+	light := !now.Before(lightAt) && now.Before(darkAt)
+	if darkAt.Before(lightAt) {
+		light = !now.Before(lightAt) || now.Before(darkAt)
+	}
+
+	switch {
+	case light && now.Before(darkAt):
+		return palette.Light, darkAt
+	case light:
+		return palette.Light, s.next(now, palette.Dark)
+	case now.Before(lightAt):
+		return palette.Dark, lightAt
+	default:
+		return palette.Dark, s.next(now, palette.Light)
+	}
+}
+
+// next finds the transition to variant on the days following now.
+func (s *Schedule) next(now time.Time, variant palette.Variant) time.Time {
+	lightAt, darkAt, allDay := s.Transitions(s.nextDay(now))
+	switch {
+	case allDay != "":
+		return s.nextDay(now)
+	case variant == palette.Light:
+		return lightAt
+	default:
+		return darkAt
+	}
+}
+
+// nextDay is the midnight following now.
+func (s *Schedule) nextDay(now time.Time) time.Time {
+	year, month, day := now.Date()
+
+	return time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+}
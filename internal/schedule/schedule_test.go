@@ -0,0 +1,104 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/copydataai/altie/internal/config"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	c := require.New(t)
+
+	_, err := Parse(config.Schedule{})
+	c.ErrorIs(err, ErrNoSchedule)
+
+	_, err = Parse(config.Schedule{Mode: ModeFixed, LightTheme: "Solarized-Light.toml"})
+	c.ErrorIs(err, ErrMissingThemes)
+
+	_, err = Parse(config.Schedule{Mode: "weekly", LightTheme: "a", DarkTheme: "b"})
+	c.ErrorIs(err, ErrInvalidMode)
+
+	_, err = Parse(config.Schedule{Mode: ModeFixed, LightTheme: "a", DarkTheme: "b", LightAt: "7am", DarkAt: "19:00"})
+	c.ErrorIs(err, ErrInvalidTime)
+
+	_, err = Parse(config.Schedule{Mode: ModeFixed, LightTheme: "a", DarkTheme: "b", LightAt: "07:00", DarkAt: "25:00"})
+	c.ErrorIs(err, ErrInvalidTime)
+
+	_, err = Parse(config.Schedule{Mode: ModeSolar, LightTheme: "a", DarkTheme: "b", Latitude: 91})
+	c.ErrorIs(err, ErrInvalidCoordinates)
+
+	s, err := Parse(config.Schedule{Mode: ModeFixed, LightTheme: "a", DarkTheme: "b", LightAt: "07:30", DarkAt: "19:00"})
+	c.NoError(err)
+	c.Equal(7*60+30, s.lightAt)
+	c.Equal("a", s.Theme(palette.Light))
+	c.Equal("b", s.Theme(palette.Dark))
+}
+
+func TestFixedSchedule(t *testing.T) {
+	c := require.New(t)
+
+	s, err := Parse(config.Schedule{Mode: ModeFixed, LightTheme: "a", DarkTheme: "b", LightAt: "07:00", DarkAt: "19:00"})
+	c.NoError(err)
+
+	day := func(d, hour, minute int) time.Time {
+		return time.Date(2024, 5, d, hour, minute, 0, 0, time.UTC)
+	}
+
+	variant, next := s.At(day(10, 6, 59))
+	c.Equal(palette.Dark, variant)
+	c.Equal(day(10, 7, 0), next)
+
+	variant, next = s.At(day(10, 7, 0))
+	c.Equal(palette.Light, variant)
+	c.Equal(day(10, 19, 0), next)
+
+	variant, next = s.At(day(10, 23, 0))
+	c.Equal(palette.Dark, variant)
+	c.Equal(day(11, 7, 0), next)
+
+	// A light theme at night
+	s, err = Parse(config.Schedule{Mode: ModeFixed, LightTheme: "a", DarkTheme: "b", LightAt: "22:00", DarkAt: "06:00"})
+	c.NoError(err)
+
+	variant, next = s.At(day(10, 23, 0))
+	c.Equal(palette.Light, variant)
+	c.Equal(day(11, 6, 0), next)
+
+	variant, next = s.At(day(10, 3, 0))
+	c.Equal(palette.Light, variant)
+	c.Equal(day(10, 6, 0), next)
+
+	variant, next = s.At(day(10, 12, 0))
+	c.Equal(palette.Dark, variant)
+	c.Equal(day(10, 22, 0), next)
+}
+
+func TestSolarSchedule(t *testing.T) {
+	c := require.New(t)
+
+	bst := time.FixedZone("BST", 3600)
+	s, err := Parse(config.Schedule{Mode: ModeSolar, LightTheme: "a", DarkTheme: "b", Latitude: 51.5074, Longitude: -0.1278})
+	c.NoError(err)
+
+	variant, next := s.At(time.Date(2024, 6, 21, 12, 0, 0, 0, bst))
+	c.Equal(palette.Light, variant)
+	c.WithinDuration(time.Date(2024, 6, 21, 21, 21, 0, 0, bst), next, 2*time.Minute)
+
+	variant, next = s.At(time.Date(2024, 6, 21, 23, 0, 0, 0, bst))
+	c.Equal(palette.Dark, variant)
+	c.WithinDuration(time.Date(2024, 6, 22, 4, 43, 0, 0, bst), next, 2*time.Minute)
+
+	// Without sunset the schedule is checked again the next day
+	s, err = Parse(config.Schedule{Mode: ModeSolar, LightTheme: "a", DarkTheme: "b", Latitude: 69.6492, Longitude: 18.9553})
+	c.NoError(err)
+
+	variant, next = s.At(time.Date(2024, 6, 21, 23, 0, 0, 0, time.UTC))
+	c.Equal(palette.Light, variant)
+	c.Equal(time.Date(2024, 6, 22, 0, 0, 0, 0, time.UTC), next)
+
+	variant, _ = s.At(time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC))
+	c.Equal(palette.Dark, variant)
+}
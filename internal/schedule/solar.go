@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// zenith of the sun at sunrise and sunset, below the horizon by its
+// radius and the atmospheric refraction.
+const zenith = 90.833
+
+var (
+	ErrPolarDay   = errors.New("the sun doesn't set that day")
+	ErrPolarNight = errors.New("the sun doesn't rise that day")
+)
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// normalize brings v within [0, max).
+func normalize(v, max float64) float64 {
+	return math.Mod(math.Mod(v, max)+max, max)
+}
+
+// SunTimes computes the sunrise and sunset of the day of date, in its
+// location, at the given latitude and longitude in degrees, north and east
+// being positive. It follows the Almanac for Computers algorithm, which is
+// within a couple of minutes below the polar circles.
+func SunTimes(date time.Time, latitude, longitude float64) (time.Time, time.Time, error) {
+	year, month, day := date.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	dayOfYear := float64(date.YearDay())
+	lngHour := longitude / 15
+
+	// Sunrise comes before the solar noon of the day, sunset after it
+	noon := 12 - lngHour
+
+	sunrise, err := sunEvent(dayOfYear, latitude, lngHour, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	for sunrise > noon {
+		sunrise -= 24
+	}
+
+	for sunrise < noon-24 {
+		sunrise += 24
+	}
+
+	sunset, err := sunEvent(dayOfYear, latitude, lngHour, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	for sunset < noon {
+		sunset += 24
+	}
+
+	for sunset > noon+24 {
+		sunset -= 24
+	}
+
+	at := func(hours float64) time.Time {
+		return midnight.Add(time.Duration(hours * float64(time.Hour))).In(date.Location())
+	}
+
+	return at(sunrise), at(sunset), nil
+}
+
+// sunEvent returns the UTC hour of the sunrise, or the sunset.
+func sunEvent(dayOfYear, latitude, lngHour float64, rising bool) (float64, error) {
+	t := dayOfYear + (18-lngHour)/24
+	if rising {
+		t = dayOfYear + (6-lngHour)/24
+	}
+
+	// Mean anomaly and true longitude of the sun
+	m := 0.9856*t - 3.289
+	l := normalize(m+1.916*math.Sin(radians(m))+0.020*math.Sin(radians(2*m))+282.634, 360)
+
+	// Right ascension, in the same quadrant as the longitude, in hours
+	ra := normalize(degrees(math.Atan(0.91764*math.Tan(radians(l)))), 360)
+	ra += math.Floor(l/90)*90 - math.Floor(ra/90)*90
+	ra /= 15
+
+	// Declination and hour angle of the sun
+	sinDec := 0.39782 * math.Sin(radians(l))
+	cosDec := math.Cos(math.Asin(sinDec))
+
+	cosH := (math.Cos(radians(zenith)) - sinDec*math.Sin(radians(latitude))) / (cosDec * math.Cos(radians(latitude)))
+	switch {
+	case cosH > 1:
+		return 0, ErrPolarNight
+	case cosH < -1:
+		return 0, ErrPolarDay
+	}
+
+	h := degrees(math.Acos(cosH))
+	if rising {
+		h = 360 - h
+	}
+
+	localMean := h/15 + ra - 0.06571*t - 6.622
+
+	return normalize(localMean-lngHour, 24), nil
+}
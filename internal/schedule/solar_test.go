@@ -0,0 +1,62 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSunTimes(t *testing.T) {
+	c := require.New(t)
+
+	bst := time.FixedZone("BST", 3600)
+	gmt := time.FixedZone("GMT", 0)
+	edt := time.FixedZone("EDT", -4*3600)
+	aedt := time.FixedZone("AEDT", 11*3600)
+
+	// Published sunrise and sunset times, to the minute
+	cases := []struct {
+		name                string
+		latitude, longitude float64
+		date                time.Time
+		sunrise, sunset     time.Time
+	}{
+		{
+			"London, summer solstice", 51.5074, -0.1278,
+			time.Date(2024, 6, 21, 12, 0, 0, 0, bst),
+			time.Date(2024, 6, 21, 4, 43, 0, 0, bst), time.Date(2024, 6, 21, 21, 21, 0, 0, bst),
+		},
+		{
+			"London, winter solstice", 51.5074, -0.1278,
+			time.Date(2024, 12, 21, 12, 0, 0, 0, gmt),
+			time.Date(2024, 12, 21, 8, 4, 0, 0, gmt), time.Date(2024, 12, 21, 15, 53, 0, 0, gmt),
+		},
+		{
+			"New York, equinox", 40.7128, -74.0060,
+			time.Date(2024, 3, 20, 12, 0, 0, 0, edt),
+			time.Date(2024, 3, 20, 6, 59, 0, 0, edt), time.Date(2024, 3, 20, 19, 10, 0, 0, edt),
+		},
+		{
+			// Sunrise is the day before in UTC
+			"Sydney, new year", -33.8688, 151.2093,
+			time.Date(2024, 1, 1, 12, 0, 0, 0, aedt),
+			time.Date(2024, 1, 1, 5, 47, 0, 0, aedt), time.Date(2024, 1, 1, 20, 9, 0, 0, aedt),
+		},
+	}
+
+	for _, tc := range cases {
+		sunrise, sunset, err := SunTimes(tc.date, tc.latitude, tc.longitude)
+		c.NoError(err, tc.name)
+		c.WithinDuration(tc.sunrise, sunrise, 2*time.Minute, tc.name)
+		c.WithinDuration(tc.sunset, sunset, 2*time.Minute, tc.name)
+		c.Equal(tc.date.Location(), sunrise.Location(), tc.name)
+	}
+
+	// Tromsø, above the arctic circle
+	_, _, err := SunTimes(time.Date(2024, 12, 21, 12, 0, 0, 0, gmt), 69.6492, 18.9553)
+	c.ErrorIs(err, ErrPolarNight)
+
+	_, _, err = SunTimes(time.Date(2024, 6, 21, 12, 0, 0, 0, gmt), 69.6492, 18.9553)
+	c.ErrorIs(err, ErrPolarDay)
+}
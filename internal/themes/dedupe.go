@@ -0,0 +1,105 @@
+package themes
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// Duplicates are themes whose palette matches the one of Original.
+type Duplicates struct {
+	Original string
+	Themes   []Match
+}
+
+// Duplicates groups the indexed themes whose palettes are within tolerance
+// of each other, a tolerance of 0 only groups identical palettes. The first
+// theme by name is the original of its group, hidden themes are included.
+func (idx *Index) Duplicates(tolerance float64) []Duplicates {
+	entries := idx.Entries(Filter{IncludeHidden: true})
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	grouped := make([]bool, len(entries))
+	groups := make([]Duplicates, 0)
+	for i, original := range entries {
+		if grouped[i] || original.Palette == nil {
+			continue
+		}
+
+		group := Duplicates{Original: original.Name}
+		for j := i + 1; j < len(entries); j++ {
+			candidate := entries[j]
+			if grouped[j] || candidate.Palette == nil {
+				continue
+			}
+
+			distance, ok := paletteDistance(original.Palette, candidate.Palette, tolerance)
+			if !ok {
+				continue
+			}
+
+			grouped[j] = true
+			group.Themes = append(group.Themes, Match{Name: candidate.Name, Distance: distance})
+		}
+
+		if len(group.Themes) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// paletteDistance reports whether two palettes are within tolerance,
+// comparing the backgrounds first to skip most pairs cheaply.
+func paletteDistance(a, b *palette.Palette, tolerance float64) (float64, bool) {
+	if palette.Same(a, b) {
+		return 0, true
+	}
+
+	if tolerance == 0 {
+		return 0, false
+	}
+
+	// The mean over the 18 colors can't be under tolerance if one of them
+	// is 18 times over it
+	if palette.DeltaE(a.Background, b.Background) > 18*tolerance {
+		return 0, false
+	}
+
+	distance := palette.Distance(a, b)
+
+	return distance, distance <= tolerance
+}
+
+// Hide hides the named themes from the picker and listings.
+func (idx *Index) Hide(names []string) {
+	hidden := make(map[string]bool, len(idx.Hidden))
+	for _, name := range idx.Hidden {
+		hidden[name] = true
+	}
+
+	for _, name := range names {
+		if !hidden[name] {
+			hidden[name] = true
+			idx.Hidden = append(idx.Hidden, name)
+		}
+	}
+
+	sort.Strings(idx.Hidden)
+}
+
+// Forget drops the named themes from the index once their files are gone,
+// hidden ones included.
+func (idx *Index) Forget(names []string) {
+	idx.Hidden = slices.DeleteFunc(idx.Hidden, func(name string) bool {
+		return slices.Contains(names, name)
+	})
+
+	idx.Themes = slices.DeleteFunc(idx.Themes, func(entry IndexEntry) bool {
+		return slices.Contains(names, entry.Name)
+	})
+}
@@ -0,0 +1,89 @@
+package themes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicates(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(themesDir, os.ModePerm))
+
+	writeTestTheme(c, themesDir, "Nord.toml", "#2e3440")
+	writeTestTheme(c, themesDir, "Nord-Copy.toml", "#2e3440")
+	writeTestTheme(c, themesDir, "Nord-Tweaked.toml", "#2e3441")
+	writeTestTheme(c, themesDir, "Day.toml", "#fdf6e3")
+	c.NoError(os.WriteFile(filepath.Join(themesDir, "Empty.yml"), nil, 0o644))
+
+	index, err := BuildIndex(themesDir)
+	c.NoError(err)
+
+	groups := index.Duplicates(0)
+	c.Equal([]Duplicates{{Original: "Nord-Copy.toml", Themes: []Match{{Name: "Nord.toml"}}}}, groups)
+
+	groups = index.Duplicates(0.5)
+	c.Len(groups, 1)
+	c.Equal("Nord-Copy.toml", groups[0].Original)
+	c.Len(groups[0].Themes, 2)
+	c.Equal("Nord-Tweaked.toml", groups[0].Themes[0].Name)
+	c.Greater(groups[0].Themes[0].Distance, 0.0)
+	c.Equal(Match{Name: "Nord.toml"}, groups[0].Themes[1])
+
+	c.Len(index.Duplicates(100), 1)
+	c.Len(index.Duplicates(100)[0].Themes, 3)
+}
+
+func TestHide(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(themesDir, os.ModePerm))
+
+	writeTestTheme(c, themesDir, "Nord.toml", "#2e3440")
+	writeTestTheme(c, themesDir, "Nord-Copy.toml", "#2e3440")
+
+	indexPath := filepath.Join(tmpDir, "index.json")
+	index, err := LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+
+	index.Hide([]string{"Nord.toml"})
+	index.Hide([]string{"Nord.toml"})
+	c.Equal([]string{"Nord.toml"}, index.Hidden)
+	c.Equal([]string{"Nord-Copy.toml"}, index.Filter(Filter{}))
+	c.Len(index.Filter(Filter{IncludeHidden: true}), 2)
+	c.NoError(index.Save(indexPath))
+
+	// Hidden themes survive the index being rebuilt
+	writeTestTheme(c, themesDir, "Day.toml", "#fdf6e3")
+	c.NoError(os.Chtimes(themesDir, index.BuiltAt.Add(time.Minute), index.BuiltAt.Add(time.Minute)))
+
+	index, err = LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.Len(index.Themes, 3)
+	c.Equal([]string{"Nord.toml"}, index.Hidden)
+	c.NotContains(index.Filter(Filter{}), "Nord.toml")
+
+	// but not the themes directory changing
+	otherDir := filepath.Join(tmpDir, "other")
+	c.NoError(os.MkdirAll(otherDir, os.ModePerm))
+
+	index, err = LoadIndex(indexPath, otherDir)
+	c.NoError(err)
+	c.Empty(index.Hidden)
+}
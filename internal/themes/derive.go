@@ -0,0 +1,80 @@
+package themes
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/copydataai/altie/internal/palette"
+)
+
+type pairKey struct {
+	text       string
+	background string
+}
+
+// pairKeys maps the palette pairs to the keys holding their colors in an
+// Alacritty theme.
+var pairKeys = map[string]pairKey{
+	"foreground":    {"colors.primary.foreground", "colors.primary.background"},
+	"selection":     {"colors.selection.text", "colors.selection.background"},
+	"cursor":        {"colors.cursor.text", "colors.cursor.cursor"},
+	"search match":  {"colors.search.matches.foreground", "colors.search.matches.background"},
+	"focused match": {"colors.search.focused_match.foreground", "colors.search.focused_match.background"},
+}
+
+func init() {
+	for _, name := range palette.ANSINames {
+		pairKeys["normal "+name] = pairKey{"colors.normal." + name, "colors.primary.background"}
+		pairKeys["bright "+name] = pairKey{"colors.bright." + name, "colors.primary.background"}
+	}
+}
+
+// DeriveTheme returns the content of the theme in themePath with the
+// adjusted colors replaced, keeping everything else it sets. The background
+// each color was adjusted against is written too, so a background that
+// followed the foreground doesn't move with it. Legacy YAML themes are
+// derived as TOML.
+func DeriveTheme(themePath string, adjustments []palette.Adjustment) ([]byte, error) {
+	original, err := ReadTheme(themePath)
+	if err != nil {
+		return nil, err
+	}
+
+	theme := make(map[string]any)
+	if _, err := toml.Decode(string(original), &theme); err != nil {
+		return nil, err
+	}
+
+	for _, adjustment := range adjustments {
+		key, ok := pairKeys[adjustment.Pair]
+		if !ok {
+			return nil, fmt.Errorf("no theme key for %q", adjustment.Pair)
+		}
+
+		setKey(theme, strings.Split(key.text, "."), adjustment.To.Hex())
+		setKey(theme, strings.Split(key.background, "."), adjustment.Background.Hex())
+	}
+
+	content := &bytes.Buffer{}
+	err = toml.NewEncoder(content).Encode(theme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode TOML theme: %w", err)
+	}
+
+	return content.Bytes(), nil
+}
+
+func setKey(table map[string]any, key []string, value string) {
+	for _, name := range key[:len(key)-1] {
+		child, ok := table[name].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			table[name] = child
+		}
+		table = child
+	}
+
+	table[key[len(key)-1]] = value
+}
@@ -0,0 +1,76 @@
+package themes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveTheme(t *testing.T) {
+	c := require.New(t)
+
+	dracula := filepath.Join(repoThemesDirectory(c), "Dracula.toml")
+	original, err := LoadPalette(dracula)
+	c.NoError(err)
+
+	adjustments := []palette.Adjustment{
+		{Pair: "normal red", From: original.Normal[1], To: mustHex(c, "#ff8888"), Background: original.Background},
+		{Pair: "selection", From: original.SelectionText, To: mustHex(c, "#ffffff"), Background: original.SelectionBackground},
+		{Pair: "search match", From: original.MatchText, To: mustHex(c, "#000000"), Background: original.MatchBackground},
+	}
+
+	content, err := DeriveTheme(dracula, adjustments)
+	c.NoError(err)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	derivedPath := filepath.Join(tmpDir, "Dracula.accessible.toml")
+	c.NoError(os.WriteFile(derivedPath, content, 0o644))
+
+	derived, err := LoadPalette(derivedPath)
+	c.NoError(err)
+
+	expected := *original
+	expected.Normal[1] = mustHex(c, "#ff8888")
+	expected.SelectionText = mustHex(c, "#ffffff")
+	expected.MatchText = mustHex(c, "#000000")
+	c.Equal(expected, *derived)
+
+	// Sections the palette doesn't model are kept
+	c.Contains(string(content), "[colors.hints.start]")
+
+	// Cell references used as backgrounds are pinned to what was checked
+	nord := filepath.Join(repoThemesDirectory(c), "Nord.toml")
+	nordPalette, err := LoadPalette(nord)
+	c.NoError(err)
+
+	content, err = DeriveTheme(nord, []palette.Adjustment{
+		{Pair: "foreground", From: nordPalette.Foreground, To: mustHex(c, "#ffffff"), Background: nordPalette.Background},
+		{Pair: "cursor", From: nordPalette.CursorText, To: mustHex(c, "#000000"), Background: nordPalette.Cursor},
+	})
+	c.NoError(err)
+	c.Contains(string(content), `cursor = "#d8dee9"`)
+
+	_, err = DeriveTheme(nord, []palette.Adjustment{{Pair: "unknown"}})
+	c.Error(err)
+
+	// Legacy YAML themes are derived as TOML
+	legacyPath := filepath.Join(tmpDir, "Legacy.yml")
+	c.NoError(os.WriteFile(legacyPath, []byte("colors:\n  primary:\n    background: '0x2E3440'\n    foreground: '0xd8dee9'\n"), 0o644))
+
+	content, err = DeriveTheme(legacyPath, []palette.Adjustment{
+		{Pair: "normal red", From: nordPalette.Normal[1], To: mustHex(c, "#ff8888"), Background: nordPalette.Background},
+	})
+	c.NoError(err)
+	c.Contains(string(content), `foreground = "#d8dee9"`)
+	c.Contains(string(content), `red = "#ff8888"`)
+
+	_, err = DeriveTheme("NonExisting.toml", nil)
+	c.True(os.IsNotExist(err))
+}
@@ -0,0 +1,318 @@
+package themes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/copydataai/altie/internal/palette"
+)
+
+// indexVersion is bumped whenever IndexEntry gains a field, so older
+// caches are rebuilt instead of filtering on zero values.
+const indexVersion = 3
+
+// Where the themes of the index come from
+const (
+	SourceLocal    = "local"
+	SourceGithub   = "github"
+	SourceDerived  = "derived"
+	SourceImported = "imported"
+)
+
+// IndexEntry caches what altie computed about a theme file.
+type IndexEntry struct {
+	Name     string           `json:"name"`
+	Path     string           `json:"path"`
+	Hash     string           `json:"hash"`
+	Size     int64            `json:"size"`
+	ModTime  time.Time        `json:"modTime"`
+	Source   string           `json:"source"`
+	Variant  palette.Variant  `json:"variant,omitempty"`
+	Contrast float64          `json:"contrast,omitempty"`
+	Palette  *palette.Palette `json:"palette,omitempty"`
+}
+
+// Match is a theme ranked by its distance to what was looked for.
+type Match struct {
+	Name     string
+	Distance float64
+}
+
+// Index caches the themes directory so listing and filtering don't parse
+// every theme. Its entries are named like ListThemes names them.
+type Index struct {
+	Version         int          `json:"version"`
+	ThemesDirectory string       `json:"themesDirectory"`
+	BuiltAt         time.Time    `json:"builtAt"`
+	Themes          []IndexEntry `json:"themes"`
+	Hidden          []string     `json:"hidden,omitempty"`
+}
+
+// Category is the subdirectory of the themes directory holding the theme,
+// empty for the themes at its top.
+func (entry IndexEntry) Category() string {
+	return Category(entry.Name)
+}
+
+// Filter selects themes from the index, empty fields match every theme.
+type Filter struct {
+	Variant     palette.Variant
+	MinContrast float64
+	Category    string
+	// Names keeps only these themes when it isn't nil, like the favorites
+	Names []string
+	// IncludeHidden lists the themes hidden from the picker too
+	IncludeHidden bool
+}
+
+func BuildIndex(themesDirectory string) (*Index, error) {
+	index := &Index{
+		Version:         indexVersion,
+		ThemesDirectory: themesDirectory,
+	}
+
+	_, err := index.Refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// LoadIndex reads the index cached in indexPath, refreshes it against the
+// themes directory and saves it back when anything changed.
+func LoadIndex(indexPath, themesDirectory string) (*Index, error) {
+	index, err := readIndex(indexPath)
+	if err != nil || index.Version != indexVersion || index.ThemesDirectory != themesDirectory {
+		rebuilt := &Index{Version: indexVersion, ThemesDirectory: themesDirectory}
+
+		// Hidden themes and where themes come from are only known from the
+		// cache, they outlive rebuilds. Entries holding nothing but their
+		// source are read again by Refresh.
+		if err == nil && index.ThemesDirectory == themesDirectory {
+			rebuilt.Hidden = index.Hidden
+			for _, entry := range index.Themes {
+				rebuilt.Themes = append(rebuilt.Themes, IndexEntry{Name: entry.Name, Source: entry.Source})
+			}
+		}
+
+		index = rebuilt
+	}
+
+	changed, err := index.Refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	if !changed {
+		return index, nil
+	}
+
+	err = index.Save(indexPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// Refresh brings the index up to date with the themes directory, reading
+// only the files whose size or modification time changed. It reports
+// whether any entry changed.
+func (idx *Index) Refresh() (bool, error) {
+	files, err := discoverThemes(idx.ThemesDirectory)
+	if err != nil {
+		return false, err
+	}
+
+	previous := make(map[string]IndexEntry, len(idx.Themes))
+	for _, entry := range idx.Themes {
+		previous[entry.Name] = entry
+	}
+
+	changed := len(files) != len(idx.Themes)
+	entries := make([]IndexEntry, 0, len(files))
+	for _, file := range files {
+		entry, ok := previous[file.name]
+		if !ok || entry.Size != file.info.Size() || !entry.ModTime.Equal(file.info.ModTime()) {
+			entry, err = readEntry(file, entry)
+			if err != nil {
+				return false, err
+			}
+			changed = true
+		}
+
+		entries = append(entries, entry)
+	}
+
+	idx.Themes = entries
+	if changed {
+		idx.BuiltAt = time.Now()
+	}
+
+	return changed, nil
+}
+
+// readEntry computes the entry of a new or changed file. The palette of the
+// previous entry is kept when the content didn't change.
+func readEntry(file themeFileInfo, previous IndexEntry) (IndexEntry, error) {
+	content, err := os.ReadFile(file.path)
+	if err != nil {
+		return IndexEntry{}, err
+	}
+
+	sum := sha256.Sum256(content)
+
+	entry := previous
+	entry.Name = file.name
+	entry.Path = file.path
+	entry.Hash = hex.EncodeToString(sum[:])
+	entry.Size = file.info.Size()
+	entry.ModTime = file.info.ModTime()
+	if entry.Source == "" {
+		entry.Source = SourceLocal
+	}
+
+	if entry.Hash == previous.Hash {
+		return entry, nil
+	}
+
+	// Files that aren't valid themes stay listed without a variant
+	entry.Variant, entry.Contrast, entry.Palette = "", 0, nil
+	themePalette, err := DecodePalette(file.path, content)
+	if err == nil {
+		entry.Variant = themePalette.Variant()
+		entry.Contrast = themePalette.PrimaryContrast()
+		entry.Palette = themePalette
+	}
+
+	return entry, nil
+}
+
+func readIndex(indexPath string) (*Index, error) {
+	content, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &Index{}
+	err = json.Unmarshal(content, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func (idx *Index) Save(indexPath string) error {
+	content, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(indexPath, content, 0o644)
+}
+
+// Entries returns the indexed themes matching filter.
+func (idx *Index) Entries(filter Filter) []IndexEntry {
+	hidden := make(map[string]bool, len(idx.Hidden))
+	if !filter.IncludeHidden {
+		for _, name := range idx.Hidden {
+			hidden[name] = true
+		}
+	}
+
+	var names map[string]bool
+	if filter.Names != nil {
+		names = make(map[string]bool, len(filter.Names))
+		for _, name := range filter.Names {
+			names[name] = true
+		}
+	}
+
+	entries := make([]IndexEntry, 0, len(idx.Themes))
+	for _, entry := range idx.Themes {
+		if hidden[entry.Name] {
+			continue
+		}
+
+		if names != nil && !names[entry.Name] {
+			continue
+		}
+
+		if filter.Variant != "" && entry.Variant != filter.Variant {
+			continue
+		}
+
+		if entry.Contrast < filter.MinContrast {
+			continue
+		}
+
+		if filter.Category != "" && entry.Category() != filter.Category {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// Filter returns the names of the indexed themes matching filter.
+func (idx *Index) Filter(filter Filter) []string {
+	entries := idx.Entries(filter)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+
+	return names
+}
+
+// Rank sorts the themes matching filter by distance, closest first.
+// Themes whose palette couldn't be read are left out.
+func (idx *Index) Rank(filter Filter, distance func(*palette.Palette) float64) []Match {
+	matches := make([]Match, 0, len(idx.Themes))
+	for _, entry := range idx.Entries(filter) {
+		if entry.Palette == nil {
+			continue
+		}
+
+		matches = append(matches, Match{Name: entry.Name, Distance: distance(entry.Palette)})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Distance < matches[j].Distance
+	})
+
+	return matches
+}
+
+// SetSource records where the named themes come from.
+func (idx *Index) SetSource(source string, names ...string) {
+	sources := make(map[string]bool, len(names))
+	for _, name := range names {
+		sources[name] = true
+	}
+
+	for i := range idx.Themes {
+		if sources[idx.Themes[i].Name] {
+			idx.Themes[i].Source = source
+		}
+	}
+}
+
+// Entry returns the indexed theme with the given name.
+func (idx *Index) Entry(name string) (IndexEntry, bool) {
+	for _, entry := range idx.Themes {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+
+	return IndexEntry{}, false
+}
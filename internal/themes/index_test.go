@@ -0,0 +1,265 @@
+package themes
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/stretchr/testify/require"
+)
+
+const testTheme = `[colors.primary]
+background = "%s"
+foreground = "#d8dee9"
+
+[colors.normal]
+black = "#3b4252"
+red = "#bf616a"
+green = "#a3be8c"
+yellow = "#ebcb8b"
+blue = "#81a1c1"
+magenta = "#b48ead"
+cyan = "#88c0d0"
+white = "#e5e9f0"
+
+[colors.bright]
+black = "#4c566a"
+red = "#bf616a"
+green = "#a3be8c"
+yellow = "#ebcb8b"
+blue = "#81a1c1"
+magenta = "#b48ead"
+cyan = "#8fbcbb"
+white = "#eceff4"
+`
+
+func writeTestTheme(c *require.Assertions, dir, name, background string) {
+	content := []byte(fmt.Sprintf(testTheme, background))
+	c.NoError(os.WriteFile(filepath.Join(dir, name), content, 0o644))
+}
+
+func TestBuildIndex(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(themesDir, os.ModePerm))
+
+	writeTestTheme(c, themesDir, "Night.toml", "#2e3440")
+	writeTestTheme(c, themesDir, "Day.toml", "#fdf6e3")
+	c.NoError(os.WriteFile(filepath.Join(themesDir, "Empty.yml"), nil, 0o644))
+
+	index, err := BuildIndex(themesDir)
+	c.NoError(err)
+	c.Equal(themesDir, index.ThemesDirectory)
+	c.Len(index.Themes, 3)
+
+	variants := map[string]palette.Variant{}
+	for _, entry := range index.Themes {
+		variants[entry.Name] = entry.Variant
+	}
+	c.Equal(map[string]palette.Variant{"Night.toml": palette.Dark, "Day.toml": palette.Light, "Empty.yml": ""}, variants)
+
+	c.ElementsMatch([]string{"Night.toml"}, index.Filter(Filter{Variant: palette.Dark}))
+	c.ElementsMatch([]string{"Day.toml"}, index.Filter(Filter{Variant: palette.Light}))
+	c.Len(index.Filter(Filter{}), 3)
+
+	// #d8dee9 reaches 11:1 over the dark background but not 1.5:1 over the light one
+	c.ElementsMatch([]string{"Night.toml"}, index.Filter(Filter{MinContrast: palette.WCAGAA}))
+	c.Empty(index.Filter(Filter{Variant: palette.Light, MinContrast: 1.5}))
+}
+
+func TestLoadIndex(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(filepath.Join(themesDir, "nested"), os.ModePerm))
+	writeTestTheme(c, themesDir, "Night.toml", "#2e3440")
+
+	indexPath := filepath.Join(tmpDir, "index.json")
+
+	index, err := LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.Len(index.Themes, 1)
+
+	night := index.Themes[0]
+	c.Equal("Night.toml", night.Name)
+	c.Equal(filepath.Join(themesDir, "Night.toml"), night.Path)
+	c.Equal(SourceLocal, night.Source)
+	c.Len(night.Hash, 64)
+	c.NotZero(night.Size)
+	c.False(night.ModTime.IsZero())
+
+	_, err = os.Stat(indexPath)
+	c.NoError(err)
+
+	// A fresh cache is read back instead of being rebuilt
+	cached, err := LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.True(index.BuiltAt.Equal(cached.BuiltAt))
+
+	// Caches written by another version are rebuilt, keeping the sources
+	cached.Version = 0
+	cached.SetSource(SourceGithub, "Night.toml")
+	c.NoError(cached.Save(indexPath))
+	rebuilt, err := LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.Equal(indexVersion, rebuilt.Version)
+
+	night, ok := rebuilt.Entry("Night.toml")
+	c.True(ok)
+	c.Equal(SourceGithub, night.Source)
+	c.Equal(palette.Dark, night.Variant)
+	c.Len(night.Hash, 64)
+
+	// Nested themes are named by their path in the themes directory
+	writeTestTheme(c, filepath.Join(themesDir, "nested"), "Day.toml", "#fdf6e3")
+
+	index, err = LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.Len(index.Themes, 2)
+
+	day, ok := index.Entry("nested/Day.toml")
+	c.True(ok)
+	c.Equal(palette.Light, day.Variant)
+	c.Equal(filepath.Join(themesDir, "nested", "Day.toml"), day.Path)
+
+	_, ok = index.Entry("Day.toml")
+	c.False(ok)
+
+	c.Equal("nested", day.Category())
+	c.Equal([]string{"nested/Day.toml"}, index.Filter(Filter{Category: "nested"}))
+	c.Equal([]string{"Night.toml", "nested/Day.toml"}, index.Filter(Filter{}))
+	c.Equal([]string{"nested/Day.toml"}, index.Filter(Filter{Names: []string{"nested/Day.toml", "Missing.toml"}}))
+	c.Empty(index.Filter(Filter{Names: []string{}}))
+
+	c.NoError(os.Remove(day.Path))
+	index, err = LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+	c.Len(index.Themes, 1)
+
+	_, err = LoadIndex(filepath.Join(tmpDir, "missing", "index.json"), themesDir)
+	c.Error(err)
+}
+
+func TestRefresh(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	writeTestTheme(c, tmpDir, "Night.toml", "#2e3440")
+	path := filepath.Join(tmpDir, "Night.toml")
+
+	index, err := BuildIndex(tmpDir)
+	c.NoError(err)
+	index.SetSource(SourceGithub, "Night.toml")
+
+	changed, err := index.Refresh()
+	c.NoError(err)
+	c.False(changed)
+
+	// Unchanged files aren't read again, even when their content is broken
+	info, err := os.Stat(path)
+	c.NoError(err)
+	broken := bytes.Repeat([]byte("#"), int(info.Size()))
+	c.NoError(os.WriteFile(path, broken, 0o644))
+	c.NoError(os.Chtimes(path, info.ModTime(), info.ModTime()))
+
+	changed, err = index.Refresh()
+	c.NoError(err)
+	c.False(changed)
+	c.Equal(palette.Dark, index.Themes[0].Variant)
+
+	// Touching a file rereads it but keeps what the content didn't change
+	writeTestTheme(c, tmpDir, "Night.toml", "#2e3440")
+	later := info.ModTime().Add(time.Minute)
+	c.NoError(os.Chtimes(path, later, later))
+
+	hash := index.Themes[0].Hash
+	changed, err = index.Refresh()
+	c.NoError(err)
+	c.True(changed)
+	c.Equal(hash, index.Themes[0].Hash)
+	c.True(later.Equal(index.Themes[0].ModTime))
+	c.Equal(SourceGithub, index.Themes[0].Source)
+
+	// A new content is parsed again
+	writeTestTheme(c, tmpDir, "Night.toml", "#fdf6e3")
+	c.NoError(os.Chtimes(path, later.Add(time.Minute), later.Add(time.Minute)))
+
+	changed, err = index.Refresh()
+	c.NoError(err)
+	c.True(changed)
+	c.NotEqual(hash, index.Themes[0].Hash)
+	c.Equal(palette.Light, index.Themes[0].Variant)
+	c.Equal(SourceGithub, index.Themes[0].Source)
+
+	c.NoError(os.WriteFile(path, []byte("not a theme"), 0o644))
+	_, err = index.Refresh()
+	c.NoError(err)
+	c.Empty(index.Themes[0].Variant)
+	c.Nil(index.Themes[0].Palette)
+}
+
+func TestIndexRank(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(themesDir, os.ModePerm))
+
+	writeTestTheme(c, themesDir, "Night.toml", "#2e3440")
+	writeTestTheme(c, themesDir, "Dusk.toml", "#4c566a")
+	writeTestTheme(c, themesDir, "Day.toml", "#fdf6e3")
+	c.NoError(os.WriteFile(filepath.Join(themesDir, "Empty.yml"), nil, 0o644))
+
+	indexPath := filepath.Join(tmpDir, "index.json")
+	_, err = LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+
+	// Palettes survive the round trip through the cache
+	index, err := LoadIndex(indexPath, themesDir)
+	c.NoError(err)
+
+	night, err := LoadPalette(filepath.Join(themesDir, "Night.toml"))
+	c.NoError(err)
+
+	for _, entry := range index.Themes {
+		if entry.Name == "Night.toml" {
+			c.Equal(night, entry.Palette)
+		}
+	}
+
+	byBackground := func(p *palette.Palette) float64 {
+		return palette.DeltaE(night.Background, p.Background)
+	}
+
+	matches := index.Rank(Filter{}, byBackground)
+	c.Len(matches, 3)
+	c.Equal([]string{"Night.toml", "Dusk.toml", "Day.toml"}, []string{matches[0].Name, matches[1].Name, matches[2].Name})
+	c.Zero(matches[0].Distance)
+
+	matches = index.Rank(Filter{Variant: palette.Light}, byBackground)
+	c.Len(matches, 1)
+	c.Equal("Day.toml", matches[0].Name)
+}
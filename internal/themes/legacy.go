@@ -0,0 +1,85 @@
+package themes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidTheme = errors.New("invalid theme")
+
+// legacyColor is a color of a YAML theme, alacritty wrote them as
+// '0xrrggbb' before its TOML config.
+var legacyColor = regexp.MustCompile(`^0[xX][0-9a-fA-F]{6}$`)
+
+// IsLegacy tells whether the theme at path is a YAML one from before
+// Alacritty moved to TOML.
+func IsLegacy(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// ReadTheme reads the theme at path as an Alacritty TOML config.
+func ReadTheme(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ThemeContent(path, content)
+}
+
+// ThemeContent returns content, the theme at path, as an Alacritty TOML
+// config. Legacy YAML themes are converted like alacritty migrate does,
+// keeping their tables and turning their colors into "#rrggbb".
+func ThemeContent(path string, content []byte) ([]byte, error) {
+	if !IsLegacy(path) {
+		theme := make(map[string]any)
+		if _, err := toml.Decode(string(content), &theme); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTheme, path, err)
+		}
+
+		return content, nil
+	}
+
+	theme := make(map[string]any)
+	if err := yaml.Unmarshal(content, &theme); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTheme, path, err)
+	}
+
+	var out bytes.Buffer
+	if err := toml.NewEncoder(&out).Encode(convertLegacy(theme)); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTheme, path, err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// convertLegacy rewrites the colors of a decoded YAML theme, YAML lists
+// of tables like the indexed colors stay arrays of tables in TOML.
+func convertLegacy(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			value[key] = convertLegacy(item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = convertLegacy(item)
+		}
+	case string:
+		if legacyColor.MatchString(value) {
+			return "#" + strings.ToLower(value[2:])
+		}
+	}
+
+	return value
+}
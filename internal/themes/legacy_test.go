@@ -0,0 +1,72 @@
+package themes
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestThemeContent(t *testing.T) {
+	c := require.New(t)
+
+	legacy := []byte(`colors:
+  primary:
+    background: '0x2E3440'
+    foreground: '#d8dee9'
+  indexed_colors:
+    - { index: 16, color: '0xd08770' }
+window:
+  opacity: 0.9
+`)
+
+	content, err := ThemeContent("Nord.yml", legacy)
+	c.NoError(err)
+
+	theme := make(map[string]any)
+	_, err = toml.Decode(string(content), &theme)
+	c.NoError(err)
+	c.Equal(map[string]any{
+		"colors": map[string]any{
+			"primary":        map[string]any{"background": "#2e3440", "foreground": "#d8dee9"},
+			"indexed_colors": []map[string]any{{"index": int64(16), "color": "#d08770"}},
+		},
+		"window": map[string]any{"opacity": 0.9},
+	}, theme)
+
+	_, err = ThemeContent("Broken.yaml", []byte("colors: [unclosed\n"))
+	c.ErrorIs(err, ErrInvalidTheme)
+
+	_, err = ThemeContent("Broken.toml", []byte("[colors\n"))
+	c.ErrorIs(err, ErrInvalidTheme)
+
+	// TOML themes are left as they are
+	current := []byte("[colors.primary]\nbackground = \"#2e3440\"\n")
+	content, err = ThemeContent("Nord.toml", current)
+	c.NoError(err)
+	c.Equal(current, content)
+}
+
+func TestLoadLegacyPalette(t *testing.T) {
+	c := require.New(t)
+
+	nord, err := LoadPalette(filepath.Join(repoThemesDirectory(c), "Nord.toml"))
+	c.NoError(err)
+
+	content, err := EncodePalette(nord)
+	c.NoError(err)
+
+	// The same colors written the YAML way
+	theme := make(map[string]any)
+	_, err = toml.Decode(string(content), &theme)
+	c.NoError(err)
+
+	legacy, err := yaml.Marshal(theme)
+	c.NoError(err)
+
+	decoded, err := DecodePalette("Nord.yml", legacy)
+	c.NoError(err)
+	c.Equal(nord, decoded)
+}
@@ -0,0 +1,198 @@
+package themes
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/copydataai/altie/internal/palette"
+)
+
+const (
+	cellForeground = "CellForeground"
+	cellBackground = "CellBackground"
+)
+
+type alacrittyTheme struct {
+	Colors struct {
+		Primary struct {
+			Background string `toml:"background"`
+			Foreground string `toml:"foreground"`
+		} `toml:"primary"`
+		Normal ansiColors `toml:"normal"`
+		Bright ansiColors `toml:"bright"`
+		Cursor struct {
+			Cursor string `toml:"cursor"`
+			Text   string `toml:"text"`
+		} `toml:"cursor"`
+		Selection struct {
+			Background string `toml:"background"`
+			Text       string `toml:"text"`
+		} `toml:"selection"`
+		Search struct {
+			Matches      searchColors `toml:"matches"`
+			FocusedMatch searchColors `toml:"focused_match"`
+		} `toml:"search"`
+	} `toml:"colors"`
+}
+
+type ansiColors struct {
+	Black   string `toml:"black"`
+	Red     string `toml:"red"`
+	Green   string `toml:"green"`
+	Yellow  string `toml:"yellow"`
+	Blue    string `toml:"blue"`
+	Magenta string `toml:"magenta"`
+	Cyan    string `toml:"cyan"`
+	White   string `toml:"white"`
+}
+
+type searchColors struct {
+	Background string `toml:"background"`
+	Foreground string `toml:"foreground"`
+}
+
+func (ac ansiColors) list() [8]string {
+	return [8]string{ac.Black, ac.Red, ac.Green, ac.Yellow, ac.Blue, ac.Magenta, ac.Cyan, ac.White}
+}
+
+// LoadPalette reads the colors of an Alacritty theme.
+func LoadPalette(path string) (*palette.Palette, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodePalette(path, content)
+}
+
+// DecodePalette reads the colors of the content of an Alacritty theme,
+// legacy YAML ones too, errors mention it by path.
+func DecodePalette(path string, content []byte) (*palette.Palette, error) {
+	content, err := ThemeContent(path, content)
+	if err != nil {
+		return nil, err
+	}
+
+	theme := alacrittyTheme{}
+	if _, err := toml.Decode(string(content), &theme); err != nil {
+		return nil, err
+	}
+
+	p := &palette.Palette{}
+
+	if p.Background, err = palette.ParseHex(theme.Colors.Primary.Background); err != nil {
+		return nil, fmt.Errorf("%s: primary background: %w", path, err)
+	}
+
+	if p.Foreground, err = palette.ParseHex(theme.Colors.Primary.Foreground); err != nil {
+		return nil, fmt.Errorf("%s: primary foreground: %w", path, err)
+	}
+
+	if err = parseANSI(&p.Normal, theme.Colors.Normal); err != nil {
+		return nil, fmt.Errorf("%s: normal %w", path, err)
+	}
+
+	if err = parseANSI(&p.Bright, theme.Colors.Bright); err != nil {
+		return nil, fmt.Errorf("%s: bright %w", path, err)
+	}
+
+	colors := theme.Colors
+	optional := []struct {
+		name     string
+		value    string
+		fallback palette.Color
+		color    *palette.Color
+	}{
+		{"cursor", colors.Cursor.Cursor, p.Foreground, &p.Cursor},
+		{"cursor text", colors.Cursor.Text, p.Background, &p.CursorText},
+		{"selection background", colors.Selection.Background, p.Foreground, &p.SelectionBackground},
+		{"selection text", colors.Selection.Text, p.Background, &p.SelectionText},
+		{"search matches background", colors.Search.Matches.Background, palette.DefaultMatchBackground, &p.MatchBackground},
+		{"search matches foreground", colors.Search.Matches.Foreground, palette.DefaultMatchText, &p.MatchText},
+		{"search focused match background", colors.Search.FocusedMatch.Background, palette.DefaultFocusedBackground, &p.FocusedBackground},
+		{"search focused match foreground", colors.Search.FocusedMatch.Foreground, palette.DefaultFocusedText, &p.FocusedText},
+	}
+
+	for _, color := range optional {
+		*color.color, err = resolveColor(p, color.value, color.fallback)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, color.name, err)
+		}
+	}
+
+	return p, nil
+}
+
+// EncodePalette writes the palette as an Alacritty TOML theme. The search
+// colors are left out when they're Alacritty's defaults.
+func EncodePalette(p *palette.Palette) ([]byte, error) {
+	theme := make(map[string]any)
+	set := func(key string, color palette.Color) {
+		setKey(theme, strings.Split(key, "."), color.Hex())
+	}
+
+	set("colors.primary.background", p.Background)
+	set("colors.primary.foreground", p.Foreground)
+
+	for i, name := range palette.ANSINames {
+		set("colors.normal."+name, p.Normal[i])
+		set("colors.bright."+name, p.Bright[i])
+	}
+
+	set("colors.cursor.cursor", p.Cursor)
+	set("colors.cursor.text", p.CursorText)
+	set("colors.selection.background", p.SelectionBackground)
+	set("colors.selection.text", p.SelectionText)
+
+	if p.MatchBackground != palette.DefaultMatchBackground || p.MatchText != palette.DefaultMatchText {
+		set("colors.search.matches.background", p.MatchBackground)
+		set("colors.search.matches.foreground", p.MatchText)
+	}
+
+	if p.FocusedBackground != palette.DefaultFocusedBackground || p.FocusedText != palette.DefaultFocusedText {
+		set("colors.search.focused_match.background", p.FocusedBackground)
+		set("colors.search.focused_match.foreground", p.FocusedText)
+	}
+
+	// Written like the themes of the repository, without indentation
+	content := &bytes.Buffer{}
+	encoder := toml.NewEncoder(content)
+	encoder.Indent = ""
+
+	err := encoder.Encode(theme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode TOML theme: %w", err)
+	}
+
+	return content.Bytes(), nil
+}
+
+// resolveColor reads a color that may be left out or refer to the cell
+// colors, which are resolved against the primary colors.
+func resolveColor(p *palette.Palette, value string, fallback palette.Color) (palette.Color, error) {
+	switch value {
+	case "":
+		return fallback, nil
+	case cellForeground:
+		return p.Foreground, nil
+	case cellBackground:
+		return p.Background, nil
+	}
+
+	return palette.ParseHex(value)
+}
+
+func parseANSI(colors *[8]palette.Color, values ansiColors) error {
+	for i, value := range values.list() {
+		color, err := palette.ParseHex(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", palette.ANSINames[i], err)
+		}
+		colors[i] = color
+	}
+
+	return nil
+}
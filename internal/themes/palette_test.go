@@ -0,0 +1,103 @@
+package themes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/stretchr/testify/require"
+)
+
+// testThemesDir is resolved before TestGetRepoDirectory leaves the working
+// directory removed.
+var testThemesDir, _ = filepath.Abs(filepath.Join("..", "..", "themes"))
+
+func repoThemesDirectory(c *require.Assertions) string {
+	c.DirExists(testThemesDir)
+
+	return testThemesDir
+}
+
+func mustHex(c *require.Assertions, hex string) palette.Color {
+	color, err := palette.ParseHex(hex)
+	c.NoError(err)
+
+	return color
+}
+
+func TestLoadPalette(t *testing.T) {
+	c := require.New(t)
+
+	nord, err := LoadPalette(filepath.Join(repoThemesDirectory(c), "Nord.toml"))
+	c.NoError(err)
+	c.Equal(mustHex(c, "#2e3440"), nord.Background)
+	c.Equal(mustHex(c, "#d8dee9"), nord.Foreground)
+	c.Equal(mustHex(c, "#bf616a"), nord.Normal[1])
+	c.Equal(mustHex(c, "#8fbcbb"), nord.Bright[6])
+	c.Equal(palette.Dark, nord.Variant())
+
+	// Nord leaves out the cursor and selection, Alacritty's defaults apply
+	c.Equal(nord.Foreground, nord.Cursor)
+	c.Equal(nord.Background, nord.CursorText)
+	c.Equal(nord.Foreground, nord.SelectionBackground)
+	c.Equal(nord.Background, nord.SelectionText)
+	c.Equal(mustHex(c, "#ac4242"), nord.MatchBackground)
+	c.Equal(mustHex(c, "#f4bf75"), nord.FocusedBackground)
+
+	dracula, err := LoadPalette(filepath.Join(repoThemesDirectory(c), "Dracula.toml"))
+	c.NoError(err)
+	c.Equal(dracula.Foreground, dracula.Cursor)
+	c.Equal(dracula.Background, dracula.CursorText)
+	c.Equal(mustHex(c, "#44475a"), dracula.SelectionBackground)
+	c.Equal(dracula.Foreground, dracula.SelectionText)
+	c.Equal(mustHex(c, "#50fa7b"), dracula.MatchBackground)
+	c.Equal(mustHex(c, "#44475a"), dracula.MatchText)
+
+	_, err = LoadPalette("NonExisting.toml")
+	c.True(os.IsNotExist(err))
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	broken := filepath.Join(tmpDir, "broken.toml")
+	c.NoError(os.WriteFile(broken, []byte("[colors.primary]\nbackground = \"CellForeground\"\n"), 0o644))
+
+	_, err = LoadPalette(broken)
+	c.ErrorIs(err, palette.ErrInvalidColor)
+	c.ErrorContains(err, "primary background")
+
+	content, err := os.ReadFile(filepath.Join(repoThemesDirectory(c), "Nord.toml"))
+	c.NoError(err)
+	c.NoError(os.WriteFile(broken, append(content, []byte("\n[colors.cursor]\ncursor = \"None\"\n")...), 0o644))
+
+	_, err = LoadPalette(broken)
+	c.ErrorIs(err, palette.ErrInvalidColor)
+	c.ErrorContains(err, "cursor")
+}
+
+func TestEncodePalette(t *testing.T) {
+	c := require.New(t)
+
+	for _, name := range []string{"Nord.toml", "Dracula.toml"} {
+		themePalette, err := LoadPalette(filepath.Join(repoThemesDirectory(c), name))
+		c.NoError(err)
+
+		content, err := EncodePalette(themePalette)
+		c.NoError(err)
+
+		decoded, err := DecodePalette(name, content)
+		c.NoError(err)
+		c.Equal(themePalette, decoded)
+	}
+
+	nord, err := LoadPalette(filepath.Join(repoThemesDirectory(c), "Nord.toml"))
+	c.NoError(err)
+
+	content, err := EncodePalette(nord)
+	c.NoError(err)
+	c.NotContains(string(content), "search")
+	c.Contains(string(content), "[colors.primary]\nbackground = \"#2e3440\"\n")
+}
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +27,8 @@ var (
 	ErrNotOnRepoDir        = errors.New("you are not on the repo directory")
 	ErrNotFoundFilesGitHub = errors.New(fmt.Sprintf("Failed fetching %s ", githubContentDirectory))
 	ErrCouldNotDownload    = errors.New("I could download that theme")
+	ErrThemeNotFound       = errors.New("theme not found")
+	ErrAmbiguousTheme      = errors.New("theme name is ambiguous")
 )
 
 type GithubDownloader interface {
@@ -173,18 +179,54 @@ func GetRepoDirectory() (string, error) {
 	return dirPath, nil
 }
 
-func ListThemes(dirThemes string) ([]string, error) {
-	dirs := make([]string, 0)
-	err := filepath.Walk(dirThemes, func(path string, info os.FileInfo, err error) error {
+// themeExtensions are the files discovered as themes, YAML ones are the
+// legacy themes from before Alacritty moved to TOML.
+var themeExtensions = map[string]bool{
+	".toml": true,
+	".yml":  true,
+	".yaml": true,
+}
+
+type themeFileInfo struct {
+	name string
+	path string
+	info fs.FileInfo
+}
+
+// discoverThemes walks dirThemes for theme files. Subdirectories are
+// categories, so themes are named by their slash separated path in
+// dirThemes, and hidden directories are skipped. Unreadable entries are
+// skipped like a missing directory. Themes at the top come first, then
+// each category in order.
+func discoverThemes(dirThemes string) ([]themeFileInfo, error) {
+	files := make([]themeFileInfo, 0)
+	err := filepath.WalkDir(dirThemes, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil
 		}
-		nameFile := info.Name()
-		if nameFile == "themes" {
+
+		if d.IsDir() {
+			if path != dirThemes && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		dirs = append(dirs, nameFile)
+		if !themeExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		name, err := filepath.Rel(dirThemes, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, themeFileInfo{name: filepath.ToSlash(name), path: path, info: info})
 
 		return nil
 	})
@@ -192,7 +234,87 @@ func ListThemes(dirThemes string) ([]string, error) {
 		return nil, err
 	}
 
-	return dirs, nil
+	sort.SliceStable(files, func(i, j int) bool {
+		first, second := Category(files[i].name), Category(files[j].name)
+		if first != second {
+			return first < second
+		}
+
+		return files[i].name < files[j].name
+	})
+
+	return files, nil
+}
+
+// Category returns the category of a theme named by ListThemes.
+func Category(name string) string {
+	category := path.Dir(name)
+	if category == "." {
+		return ""
+	}
+
+	return category
+}
+
+// ListThemes returns the themes in dirThemes, the ones in subdirectories
+// are named "category/name.toml".
+func ListThemes(dirThemes string) ([]string, error) {
+	files, err := discoverThemes(dirThemes)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.name)
+	}
+
+	return names, nil
+}
+
+// ResolveTheme finds the file of a theme given as "name" or
+// "category/name", with or without its extension. A bare name also finds
+// a theme in a category as long as no other theme has that name.
+func ResolveTheme(dirThemes string, name string) (string, error) {
+	candidates := []string{name}
+	if !themeExtensions[filepath.Ext(name)] {
+		candidates = append(candidates, name+".toml")
+	}
+
+	for _, candidate := range candidates {
+		themePath := filepath.Join(dirThemes, filepath.FromSlash(candidate))
+		info, err := os.Stat(themePath)
+		if err == nil && !info.IsDir() {
+			return themePath, nil
+		}
+	}
+
+	files, err := discoverThemes(dirThemes)
+	if err != nil {
+		return "", err
+	}
+
+	matches := make([]themeFileInfo, 0)
+	for _, file := range files {
+		base := path.Base(file.name)
+		if base == name || strings.TrimSuffix(base, path.Ext(base)) == name {
+			matches = append(matches, file)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	case 1:
+		return matches[0].path, nil
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, match.name)
+	}
+
+	return "", fmt.Errorf("%w: %s could be %s", ErrAmbiguousTheme, name, strings.Join(names, ", "))
 }
 
 func BackUpTheme(alacrittyConfDir string) (string, error) {
@@ -215,37 +337,51 @@ func CheckAltieThemes(dirThemes string) error {
 	return nil
 }
 
+// ApplyFontTheme writes the font of themeConfig to the alacritty config,
+// the family and style of each style, the size when it's set and the
+// offsets.
 func ApplyFontTheme(pathConfig string, themeConfig *config.ThemeConfig) error {
-	font := themeConfig.Font
-	fontSize := themeConfig.FontSize
-
 	alacrittyConfig, err := CheckAlacrittyConfig(pathConfig)
 	if err != nil {
 		return err
 	}
 
+	faces := themeConfig.Faces
+
 	// TODO: change the map model to struct model
-	alacrittyConfig["font"] = map[string]any{
-		"normal": map[string]string{
-			"family": font,
-		},
-		"bold": map[string]string{
-			"family": font,
-		},
-		"italic": map[string]string{
-			"family": font,
-		},
-		"bold_italic": map[string]string{
-			"family": font,
-		},
-		"size": fontSize,
-	}
-
-	alacrittyFile, err := os.OpenFile(pathConfig, os.O_WRONLY, os.ModeAppend)
+	font := map[string]any{
+		"normal":      fontFace(themeConfig.Font, faces.Normal),
+		"bold":        fontFace(themeConfig.Font, faces.Bold),
+		"italic":      fontFace(themeConfig.Font, faces.Italic),
+		"bold_italic": fontFace(themeConfig.Font, faces.BoldItalic),
+	}
+
+	// Without a size alacritty keeps its own
+	if themeConfig.FontSize > 0 {
+		font["size"] = themeConfig.FontSize
+	}
+
+	if themeConfig.Offset != (config.FontOffset{}) {
+		font["offset"] = fontOffset(themeConfig.Offset)
+	}
+
+	if themeConfig.GlyphOffset != (config.FontOffset{}) {
+		font["glyph_offset"] = fontOffset(themeConfig.GlyphOffset)
+	}
+
+	if themeConfig.BuiltinBoxDrawing != nil {
+		font["builtin_box_drawing"] = *themeConfig.BuiltinBoxDrawing
+	}
+
+	alacrittyConfig["font"] = font
+
+	alacrittyFile, err := os.OpenFile(pathConfig, os.O_WRONLY|os.O_TRUNC, os.ModeAppend)
 	if err != nil {
 		return err
 	}
 
+	defer alacrittyFile.Close()
+
 	err = toml.NewEncoder(alacrittyFile).Encode(alacrittyConfig)
 	if err != nil {
 		return fmt.Errorf("failed to encode TOML config: %w", err)
@@ -254,6 +390,25 @@ func ApplyFontTheme(pathConfig string, themeConfig *config.ThemeConfig) error {
 	return nil
 }
 
+// fontFace is the alacritty table of a face, its family defaults to the
+// font of altie.conf and its style is only written when set.
+func fontFace(family string, face config.FontFace) map[string]string {
+	if face.Family != "" {
+		family = face.Family
+	}
+
+	table := map[string]string{"family": family}
+	if face.Style != "" {
+		table["style"] = face.Style
+	}
+
+	return table
+}
+
+func fontOffset(offset config.FontOffset) map[string]int64 {
+	return map[string]int64{"x": offset.X, "y": offset.Y}
+}
+
 func CheckAlacrittyConfig(pathConfig string) (map[string]any, error) {
 	config := make(map[string]any)
 	if _, err := toml.DecodeFile(pathConfig, &config); err != nil {
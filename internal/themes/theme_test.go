@@ -271,6 +271,46 @@ func TestListThemes(t *testing.T) {
 
 	c.NoError(errDirWithThemes)
 	c.Contains(resultDirWithThemes, expectedDirWithThemes)
+
+	// Test case 3: Categories and files that aren't themes
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	files := []string{
+		"Nord.toml",
+		"README.md",
+		"Legacy.yml",
+		"catppuccin/Mocha.toml",
+		"catppuccin/Latte.toml",
+		"catppuccin/palette.png",
+		"base16/dark/Ocean.toml",
+		".git/HEAD.toml",
+	}
+	for _, file := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(file))
+		c.NoError(os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		c.NoError(os.WriteFile(path, nil, 0o644))
+	}
+
+	result, err := ListThemes(tmpDir)
+	c.NoError(err)
+	c.Equal([]string{
+		"Legacy.yml",
+		"Nord.toml",
+		"base16/dark/Ocean.toml",
+		"catppuccin/Latte.toml",
+		"catppuccin/Mocha.toml",
+	}, result)
+}
+
+func TestCategory(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal("", Category("Nord.toml"))
+	c.Equal("catppuccin", Category("catppuccin/Mocha.toml"))
+	c.Equal("base16/dark", Category("base16/dark/Ocean.toml"))
 }
 
 func TestGetRepoDirectory(t *testing.T) {
@@ -302,6 +342,64 @@ func TestGetRepoDirectory(t *testing.T) {
 	c.Empty(dir)
 }
 
+func TestResolveTheme(t *testing.T) {
+	c := require.New(t)
+
+	dir := repoThemesDirectory(c)
+
+	path, err := ResolveTheme(dir, "Nord")
+	c.NoError(err)
+	c.Equal(filepath.Join(dir, "Nord.toml"), path)
+
+	path, err = ResolveTheme(dir, "3024.dark.toml")
+	c.NoError(err)
+	c.Equal(filepath.Join(dir, "3024.dark.toml"), path)
+
+	path, err = ResolveTheme(dir, "Baskerville - FarSide")
+	c.NoError(err)
+	c.Equal(filepath.Join(dir, "Baskerville - FarSide.toml"), path)
+
+	_, err = ResolveTheme(dir, "NonExisting")
+	c.ErrorIs(err, ErrThemeNotFound)
+
+	_, err = ResolveTheme(filepath.Dir(dir), "themes")
+	c.ErrorIs(err, ErrThemeNotFound)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	for _, file := range []string{"catppuccin/Mocha.toml", "catppuccin/Latte.toml", "rose-pine/Latte.toml"} {
+		path := filepath.Join(tmpDir, filepath.FromSlash(file))
+		c.NoError(os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		c.NoError(os.WriteFile(path, nil, 0o644))
+	}
+
+	path, err = ResolveTheme(tmpDir, "catppuccin/Mocha")
+	c.NoError(err)
+	c.Equal(filepath.Join(tmpDir, "catppuccin", "Mocha.toml"), path)
+
+	path, err = ResolveTheme(tmpDir, "Mocha")
+	c.NoError(err)
+	c.Equal(filepath.Join(tmpDir, "catppuccin", "Mocha.toml"), path)
+
+	path, err = ResolveTheme(tmpDir, "Mocha.toml")
+	c.NoError(err)
+	c.Equal(filepath.Join(tmpDir, "catppuccin", "Mocha.toml"), path)
+
+	path, err = ResolveTheme(tmpDir, "rose-pine/Latte.toml")
+	c.NoError(err)
+	c.Equal(filepath.Join(tmpDir, "rose-pine", "Latte.toml"), path)
+
+	_, err = ResolveTheme(tmpDir, "Latte")
+	c.ErrorIs(err, ErrAmbiguousTheme)
+	c.ErrorContains(err, "catppuccin/Latte.toml, rose-pine/Latte.toml")
+
+	_, err = ResolveTheme(tmpDir, "catppuccin")
+	c.ErrorIs(err, ErrThemeNotFound)
+}
+
 func TestBackUpTheme(t *testing.T) {
 	// Test case 1: Verify that the backup file is created with the correct name
 	// Set up test data
@@ -422,4 +520,31 @@ func TestApplyFontTheme(t *testing.T) {
 			"normal":      map[string]any{"family": "SpaceMono Nerd Font"},
 			"size":        int64(10),
 		}})
+
+	builtinBoxDrawing := false
+	configThemes.Faces = config.FontFaces{
+		Normal:     config.FontFace{Style: "Medium"},
+		Bold:       config.FontFace{Family: "Fira Code", Style: "Bold"},
+		BoldItalic: config.FontFace{Family: "Victor Mono"},
+	}
+	configThemes.Offset = config.FontOffset{Y: 1}
+	configThemes.GlyphOffset = config.FontOffset{X: -1, Y: 2}
+	configThemes.BuiltinBoxDrawing = &builtinBoxDrawing
+
+	err = ApplyFontTheme(appConfig.AlacrittyConfig, &configThemes)
+	c.NoError(err)
+
+	confThemes, err = CheckAlacrittyConfig(appConfig.AlacrittyConfig)
+	c.NoError(err)
+	c.Equal(confThemes, map[string]any{
+		"font": map[string]any{
+			"bold":                map[string]any{"family": "Fira Code", "style": "Bold"},
+			"bold_italic":         map[string]any{"family": "Victor Mono"},
+			"italic":              map[string]any{"family": "SpaceMono Nerd Font"},
+			"normal":              map[string]any{"family": "SpaceMono Nerd Font", "style": "Medium"},
+			"size":                int64(10),
+			"offset":              map[string]any{"x": int64(0), "y": int64(1)},
+			"glyph_offset":        map[string]any{"x": int64(-1), "y": int64(2)},
+			"builtin_box_drawing": false,
+		}})
 }
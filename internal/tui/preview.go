@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/pterm/pterm"
+)
+
+// role is what a piece of the sample code is, each drawn with a color of
+// the palette the way editors commonly map them.
+type role int
+
+const (
+	roleText role = iota
+	roleComment
+	roleKeyword
+	roleFunction
+	roleType
+	roleString
+	roleNumber
+	roleError
+)
+
+type token struct {
+	role role
+	text string
+}
+
+var sampleCode = [][]token{
+	{{roleComment, "// greet prints a greeting"}},
+	{{roleKeyword, "func"}, {roleText, " "}, {roleFunction, "greet"}, {roleText, "(name "}, {roleType, "string"}, {roleText, ") {"}},
+	{{roleText, "    count := "}, {roleNumber, "42"}},
+	{{roleText, "    fmt."}, {roleFunction, "Printf"}, {roleText, "("}, {roleString, `"hello %s\n"`}, {roleText, ", name)"}},
+	{{roleText, "    "}, {roleKeyword, "return"}, {roleText, " "}, {roleError, "errors.New(\"oops\")"}},
+	{{roleText, "}"}},
+}
+
+func (r role) color(p *palette.Palette) palette.Color {
+	switch r {
+	case roleComment:
+		return p.Bright[0]
+	case roleKeyword:
+		return p.Normal[5]
+	case roleFunction:
+		return p.Normal[4]
+	case roleType:
+		return p.Normal[6]
+	case roleString:
+		return p.Normal[2]
+	case roleNumber:
+		return p.Normal[3]
+	case roleError:
+		return p.Normal[1]
+	default:
+		return p.Foreground
+	}
+}
+
+func rgb(c palette.Color) pterm.RGB {
+	return pterm.NewRGB(c.R, c.G, c.B)
+}
+
+func block(c palette.Color, width int) string {
+	return pterm.NewRGB(c.R, c.G, c.B, true).Sprint(strings.Repeat(" ", width))
+}
+
+// previewLines draws the palette and the sample code of a theme.
+func previewLines(name string, variant palette.Variant, p *palette.Palette, width int) []string {
+	lines := []string{pterm.Bold.Sprint(name)}
+	if p == nil {
+		return append(lines, "", "The palette of this theme couldn't be read")
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("%s, contrast %.1f:1", variant, p.PrimaryContrast()),
+		"",
+		fmt.Sprintf("%s background %s", block(p.Background, 4), p.Background.Hex()),
+		fmt.Sprintf("%s foreground %s", block(p.Foreground, 4), p.Foreground.Hex()),
+		"",
+	)
+
+	normal, bright := "normal ", "bright "
+	for i := range p.Normal {
+		normal += block(p.Normal[i], 3) + " "
+		bright += block(p.Bright[i], 3) + " "
+	}
+
+	lines = append(lines, normal, bright, "")
+
+	background := rgb(p.Background)
+	for _, tokens := range sampleCode {
+		var line strings.Builder
+		length := 0
+		for _, t := range tokens {
+			text := t.text
+			if length+len(text) > width {
+				text = text[:max(0, width-length)]
+			}
+
+			line.WriteString(pterm.NewRGBStyle(rgb(t.role.color(p)), background).Sprint(text))
+			length += len(text)
+		}
+
+		line.WriteString(block(p.Background, max(0, width-length)))
+		lines = append(lines, line.String())
+	}
+
+	selection := pterm.NewRGBStyle(rgb(p.SelectionText), rgb(p.SelectionBackground)).Sprint(" selection ")
+	cursor := pterm.NewRGBStyle(rgb(p.CursorText), rgb(p.Cursor)).Sprint(" cursor ")
+	match := pterm.NewRGBStyle(rgb(p.MatchText), rgb(p.MatchBackground)).Sprint(" match ")
+
+	return append(lines, "", selection+" "+cursor+" "+match)
+}
+
+// Escape sequences recoloring the terminal itself, understood by most
+// terminals including alacritty.
+const (
+	oscPalette    = "\x1b]4;%d;%s\x07"
+	oscForeground = "\x1b]10;%s\x07"
+	oscBackground = "\x1b]11;%s\x07"
+	oscCursor     = "\x1b]12;%s\x07"
+	oscReset      = "\x1b]104\x07\x1b]110\x07\x1b]111\x07\x1b]112\x07"
+)
+
+// terminalPreview recolors the terminal running altie with p.
+func terminalPreview(p *palette.Palette) string {
+	var sequence strings.Builder
+	for i, c := range p.Normal {
+		fmt.Fprintf(&sequence, oscPalette, i, c.Hex())
+	}
+
+	for i, c := range p.Bright {
+		fmt.Fprintf(&sequence, oscPalette, i+len(p.Normal), c.Hex())
+	}
+
+	fmt.Fprintf(&sequence, oscForeground, p.Foreground.Hex())
+	fmt.Fprintf(&sequence, oscBackground, p.Background.Hex())
+	fmt.Fprintf(&sequence, oscCursor, p.Cursor.Hex())
+
+	return sequence.String()
+}
@@ -0,0 +1,360 @@
+// Package tui is the full-screen theme browser: the themes on the left and
+// a live preview of the selected one on the right.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"atomicgo.dev/keyboard"
+	"atomicgo.dev/keyboard/keys"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/picker"
+	"github.com/pterm/pterm"
+)
+
+// Smallest terminal the TUI draws in
+const (
+	minWidth  = 60
+	minHeight = 12
+)
+
+// Screen handling escape sequences
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	exitScreen  = "\x1b[?25h\x1b[?1049l"
+	clearScreen = "\x1b[H\x1b[2J"
+)
+
+const help = "enter apply  / search  f favorite  p preview in terminal  +/- font size  u undo  q quit"
+
+var (
+	titleStyle   = pterm.NewStyle(pterm.FgLightCyan, pterm.Bold)
+	statusStyle  = pterm.NewStyle(pterm.FgLightGreen)
+	warningStyle = pterm.NewStyle(pterm.FgLightYellow)
+	errorStyle   = pterm.NewStyle(pterm.FgLightRed)
+	helpStyle    = pterm.NewStyle(pterm.FgGray)
+)
+
+// Actions change the configuration. The CLI implements them with the
+// code the picker applies themes with.
+type Actions interface {
+	// Apply returns what the apply printed, like the output of its hooks
+	Apply(name string) (string, error)
+	// ToggleFavorite reports whether the theme is a favorite now
+	ToggleFavorite(name string) (bool, error)
+	// ChangeFontSize returns the new font size
+	ChangeFontSize(delta int64) (int64, error)
+	// Undo reverts the last change and describes it
+	Undo() (string, error)
+}
+
+// Theme is a theme listed by the TUI.
+type Theme struct {
+	picker.Item
+	Palette *palette.Palette
+}
+
+// Model keeps the state of the TUI. Like the picker, it's driven by
+// HandleKey so tests don't need a terminal.
+type Model struct {
+	Width  int
+	Height int
+
+	themes    map[string]Theme
+	items     []picker.Item
+	list      *picker.Picker
+	actions   Actions
+	searching bool
+	preview   bool
+	recolored bool
+	status    string
+	failed    bool
+	warned    bool
+}
+
+func New(themes []Theme, actions Actions) *Model {
+	m := &Model{
+		Width:   minWidth,
+		Height:  minHeight,
+		themes:  make(map[string]Theme, len(themes)),
+		items:   make([]picker.Item, 0, len(themes)),
+		actions: actions,
+	}
+
+	for _, theme := range themes {
+		m.themes[theme.Name] = theme
+		m.items = append(m.items, theme.Item)
+	}
+
+	m.list = picker.New("", m.items)
+
+	return m
+}
+
+// selected returns the theme under the cursor.
+func (m *Model) selected() (Theme, bool) {
+	item, ok := m.list.Selected()
+	if !ok {
+		return Theme{}, false
+	}
+
+	return m.themes[item.Name], true
+}
+
+func (m *Model) report(status string, err error) {
+	m.status, m.failed, m.warned = status, err != nil, false
+	if err != nil {
+		m.status = err.Error()
+	}
+}
+
+// HandleKey updates the TUI with a key press and reports whether it quit.
+func (m *Model) HandleKey(key keys.Key) bool {
+	if key.Code == keys.CtrlC {
+		return true
+	}
+
+	if m.searching {
+		switch key.Code {
+		case keys.Enter, keys.Escape:
+			m.searching = false
+		default:
+			m.list.HandleKey(key)
+		}
+
+		return false
+	}
+
+	switch key.Code {
+	case keys.Up, keys.Down, keys.PgUp, keys.PgDown:
+		m.list.HandleKey(key)
+	case keys.Enter:
+		m.apply()
+	case keys.Escape:
+		return true
+	case keys.RuneKey:
+		return m.handleRune(key.String())
+	}
+
+	return false
+}
+
+func (m *Model) handleRune(r string) bool {
+	switch r {
+	case "q":
+		return true
+	case "j":
+		m.list.HandleKey(keys.Key{Code: keys.Down})
+	case "k":
+		m.list.HandleKey(keys.Key{Code: keys.Up})
+	case "/":
+		m.searching = true
+	case "a":
+		m.apply()
+	case "f":
+		m.toggleFavorite()
+	case "p":
+		m.preview = !m.preview
+	case "+", "=":
+		m.changeFontSize(1)
+	case "-":
+		m.changeFontSize(-1)
+	case "u":
+		description, err := m.actions.Undo()
+		m.report("Undid "+description, err)
+	}
+
+	return false
+}
+
+func (m *Model) apply() {
+	theme, ok := m.selected()
+	if !ok {
+		return
+	}
+
+	output, err := m.actions.Apply(theme.Name)
+	m.report("Applied "+theme.Name, err)
+
+	// The status line is the only place the hooks can be heard, each
+	// header stays with the output after it
+	var heard strings.Builder
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case heard.Len() == 0:
+		case strings.HasSuffix(heard.String(), ":"):
+			heard.WriteString(" ")
+		default:
+			heard.WriteString("; ")
+		}
+
+		heard.WriteString(line)
+	}
+
+	if heard.Len() > 0 {
+		m.status += ": " + heard.String()
+		m.warned = strings.Contains(output, "Warning: ")
+	}
+}
+
+func (m *Model) toggleFavorite() {
+	theme, ok := m.selected()
+	if !ok {
+		return
+	}
+
+	favorite, err := m.actions.ToggleFavorite(theme.Name)
+	if err != nil {
+		m.report("", err)
+		return
+	}
+
+	section := picker.SectionThemes
+	status := "Removed " + theme.Name + " from the favorites"
+	if favorite {
+		section = picker.SectionFavorites
+		status = "Added " + theme.Name + " to the favorites"
+	}
+
+	// Favorites are listed first, the rest keep their order
+	items := make([]picker.Item, 0, len(m.items))
+	for _, item := range m.items {
+		if item.Name == theme.Name {
+			item.Section = section
+			theme.Item = item
+			m.themes[item.Name] = theme
+		}
+
+		items = append(items, item)
+	}
+
+	m.items = items
+	m.list.SetItems(items)
+	m.report(status, nil)
+}
+
+func (m *Model) changeFontSize(delta int64) {
+	size, err := m.actions.ChangeFontSize(delta)
+	m.report(fmt.Sprintf("Font size %d", size), err)
+}
+
+// pad fills line with spaces up to width visible runes.
+func pad(line string, width int) string {
+	length := utf8.RuneCountInString(pterm.RemoveColorFromString(line))
+	if length >= width {
+		return line
+	}
+
+	return line + strings.Repeat(" ", width-length)
+}
+
+// View renders the TUI in Width columns and Height rows.
+func (m *Model) View() string {
+	width, height := max(m.Width, minWidth), max(m.Height, minHeight)
+	listWidth := width * 2 / 5
+	previewWidth := width - listWidth - 3
+	bodyHeight := height - 3
+
+	// Leave room for the titles of the three sections
+	m.list.MaxHeight = bodyHeight - 3
+
+	matches, total := m.list.Count()
+	search := ""
+	if m.searching || m.list.Query() != "" {
+		search = "  / " + m.list.Query()
+		if m.searching {
+			search += "_"
+		}
+	}
+
+	var view strings.Builder
+	fmt.Fprintf(&view, "%s %s%s\n", titleStyle.Sprint("altie"), helpStyle.Sprintf("%d/%d themes", matches, total), search)
+
+	left := m.list.Lines(listWidth)
+	right := []string{"No theme matches the search"}
+	if theme, ok := m.selected(); ok {
+		right = previewLines(theme.Name, theme.Variant, theme.Palette, previewWidth)
+	}
+
+	for row := 0; row < bodyHeight; row++ {
+		line := ""
+		if row < len(left) {
+			line = left[row]
+		}
+
+		line = pad(line, listWidth) + helpStyle.Sprint(" │ ")
+		if row < len(right) {
+			line += right[row]
+		}
+
+		fmt.Fprintf(&view, "%s\n", line)
+	}
+
+	status := statusStyle.Sprint(m.status)
+	switch {
+	case m.failed:
+		status = errorStyle.Sprint(m.status)
+	case m.warned:
+		status = warningStyle.Sprint(m.status)
+	}
+
+	fmt.Fprintf(&view, "%s\n%s\n", status, helpStyle.Sprint(help))
+
+	return view.String()
+}
+
+// Run draws the TUI on out until it's quit. Keys come from the keyboard
+// package, so keyboard.SimulateKeyPress drives it in tests.
+func (m *Model) Run(out io.Writer) error {
+	fmt.Fprint(out, enterScreen)
+	defer fmt.Fprint(out, exitScreen)
+
+	draw := func() {
+		width, height, err := pterm.GetTerminalSize()
+		if err == nil {
+			m.Width, m.Height = width, height
+		}
+
+		// The terminal is raw while keys are read, lines need a carriage return
+		fmt.Fprint(out, clearScreen+strings.ReplaceAll(m.View(), "\n", "\r\n"))
+
+		theme, ok := m.selected()
+		switch {
+		case m.preview && ok && theme.Palette != nil:
+			fmt.Fprint(out, terminalPreview(theme.Palette))
+			m.recolored = true
+		case m.recolored && !m.preview:
+			fmt.Fprint(out, oscReset)
+			m.recolored = false
+		}
+	}
+
+	draw()
+
+	// keyboard calls back from its reader and from the simulated keys
+	var mu sync.Mutex
+	err := keyboard.Listen(func(key keys.Key) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		stop := m.HandleKey(key)
+		if !stop {
+			draw()
+		}
+
+		return stop, nil
+	})
+
+	if m.recolored {
+		fmt.Fprint(out, oscReset)
+	}
+
+	return err
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"atomicgo.dev/keyboard"
+	"atomicgo.dev/keyboard/keys"
+	"github.com/copydataai/altie/internal/palette"
+	"github.com/copydataai/altie/internal/picker"
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/require"
+)
+
+type mockActions struct {
+	applied   []string
+	favorites map[string]bool
+	fontSize  int64
+	undone    int
+	output    string
+	err       error
+}
+
+func (m *mockActions) Apply(name string) (string, error) {
+	if m.err != nil {
+		return m.output, m.err
+	}
+
+	m.applied = append(m.applied, name)
+
+	return m.output, nil
+}
+
+func (m *mockActions) ToggleFavorite(name string) (bool, error) {
+	m.favorites[name] = !m.favorites[name]
+	return m.favorites[name], nil
+}
+
+func (m *mockActions) ChangeFontSize(delta int64) (int64, error) {
+	m.fontSize += delta
+	return m.fontSize, nil
+}
+
+func (m *mockActions) Undo() (string, error) {
+	m.undone++
+	return "applying Nord.toml", nil
+}
+
+func testPalette() *palette.Palette {
+	p := &palette.Palette{}
+	p.Background, _ = palette.ParseHex("#2e3440")
+	p.Foreground, _ = palette.ParseHex("#d8dee9")
+
+	return p
+}
+
+func testThemes() []Theme {
+	return []Theme{
+		{Item: picker.Item{Name: "Nord.toml", Variant: palette.Dark, Section: picker.SectionThemes}, Palette: testPalette()},
+		{Item: picker.Item{Name: "Broken.toml", Section: picker.SectionThemes}},
+		{Item: picker.Item{Name: "catppuccin/Latte.toml", Variant: palette.Light, Section: picker.SectionThemes}, Palette: testPalette()},
+	}
+}
+
+func runes(m *Model, input string) {
+	for _, r := range input {
+		m.HandleKey(keys.Key{Code: keys.RuneKey, Runes: []rune{r}})
+	}
+}
+
+func TestModel(t *testing.T) {
+	c := require.New(t)
+
+	actions := &mockActions{favorites: map[string]bool{}, fontSize: 12}
+	m := New(testThemes(), actions)
+	m.Width, m.Height = 100, 30
+
+	view := pterm.RemoveColorFromString(m.View())
+	c.Contains(view, "altie 3/3 themes")
+	c.Contains(view, "> Nord.toml")
+	c.Contains(view, "dark, contrast 9.2:1")
+	c.Contains(view, "#2e3440")
+	c.Contains(view, "func greet(name string) {")
+	c.Len(strings.Split(strings.TrimSuffix(view, "\n"), "\n"), 30)
+
+	// Moving shows the preview of the next theme
+	runes(m, "j")
+	view = pterm.RemoveColorFromString(m.View())
+	c.Contains(view, "> Broken.toml")
+	c.Contains(view, "couldn't be read")
+
+	m.HandleKey(keys.Key{Code: keys.Enter})
+	c.Equal([]string{"Broken.toml"}, actions.applied)
+	c.Contains(pterm.RemoveColorFromString(m.View()), "Applied Broken.toml")
+
+	// Favorites move to the top and stay selected
+	runes(m, "f")
+	c.True(actions.favorites["Broken.toml"])
+	lines := strings.Split(pterm.RemoveColorFromString(m.View()), "\n")
+	c.True(strings.HasPrefix(lines[1], "Favorites "))
+	c.True(strings.HasPrefix(lines[2], "> Broken.toml "))
+
+	runes(m, "+=-")
+	c.Equal(int64(13), actions.fontSize)
+	c.Contains(pterm.RemoveColorFromString(m.View()), "Font size 13")
+
+	runes(m, "u")
+	c.Equal(1, actions.undone)
+	c.Contains(pterm.RemoveColorFromString(m.View()), "Undid applying Nord.toml")
+
+	// Typing only searches after /, where q doesn't quit
+	runes(m, "/latq")
+	c.Contains(pterm.RemoveColorFromString(m.View()), "/ latq_")
+	m.HandleKey(keys.Key{Code: keys.Backspace})
+	m.HandleKey(keys.Key{Code: keys.Enter})
+	view = pterm.RemoveColorFromString(m.View())
+	c.Contains(view, "altie 1/3 themes  / lat\n")
+	c.Contains(view, "> catppuccin/Latte.toml")
+
+	runes(m, "a")
+	c.Equal([]string{"Broken.toml", "catppuccin/Latte.toml"}, actions.applied)
+
+	// What the hooks printed shows in the status line
+	actions.output = "PostApply hook \"reload\":\nreloaded\nWarning: PostApply hook failed: exit 3: exit status 3\n"
+	runes(m, "a")
+	c.Contains(pterm.RemoveColorFromString(m.View()), "Applied catppuccin/Latte.toml: PostApply hook \"reload\": reloaded; Warning: PostApply hook failed: exit 3: exit status 3\n")
+	c.True(m.warned)
+	actions.output = ""
+
+	actions.err = errors.New("alacritty config not found")
+	runes(m, "a")
+	c.Contains(pterm.RemoveColorFromString(m.View()), "alacritty config not found")
+
+	runes(m, "/zzz")
+	m.HandleKey(keys.Key{Code: keys.Escape})
+	c.Contains(pterm.RemoveColorFromString(m.View()), "No theme matches the search")
+
+	runes(m, "p")
+	c.True(m.preview)
+	c.True(m.HandleKey(keys.Key{Code: keys.RuneKey, Runes: []rune("q")}))
+}
+
+func TestTerminalPreview(t *testing.T) {
+	c := require.New(t)
+
+	sequence := terminalPreview(testPalette())
+	c.Contains(sequence, "\x1b]4;0;#000000\x07")
+	c.Contains(sequence, "\x1b]4;15;#000000\x07")
+	c.Contains(sequence, "\x1b]10;#d8dee9\x07")
+	c.Contains(sequence, "\x1b]11;#2e3440\x07")
+}
+
+func TestRun(t *testing.T) {
+	c := require.New(t)
+
+	actions := &mockActions{favorites: map[string]bool{}}
+	m := New(testThemes(), actions)
+
+	go func() {
+		keyboard.SimulateKeyPress("p")
+		keyboard.SimulateKeyPress(keys.Enter)
+		keyboard.SimulateKeyPress("q")
+	}()
+
+	out := &bytes.Buffer{}
+	c.NoError(m.Run(out))
+	c.Equal([]string{"Nord.toml"}, actions.applied)
+	c.True(strings.HasPrefix(out.String(), enterScreen))
+	c.Contains(out.String(), "\x1b]11;#2e3440\x07")
+	c.True(strings.HasSuffix(out.String(), oscReset+exitScreen))
+}
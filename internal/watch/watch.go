@@ -0,0 +1,10 @@
+// Package watch reports the files created, written, moved or deleted in
+// watched directories, so altie can follow edits to its themes and config.
+package watch
+
+import "errors"
+
+var (
+	ErrUnsupported = errors.New("watching files isn't supported on this platform")
+	ErrOverflow    = errors.New("too many changes at once, some were lost")
+)
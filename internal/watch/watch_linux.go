@@ -0,0 +1,234 @@
+//go:build linux
+
+package watch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// mask are the inotify events reported, a file is written once it's closed.
+const mask = unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+// watchedDir is a directory watched for all of its files and
+// subdirectories, or only for some files.
+type watchedDir struct {
+	path      string
+	recursive bool
+	names     map[string]bool
+}
+
+// Watcher sends the path of each changed file on Events, and what went
+// wrong while watching on Errors, until it's closed.
+type Watcher struct {
+	Events <-chan string
+	Errors <-chan error
+
+	events chan string
+	errors chan error
+	done   chan struct{}
+	file   *os.File
+	fd     int
+
+	mu   sync.Mutex
+	dirs map[int]*watchedDir
+}
+
+// New starts an inotify instance watching nothing yet.
+func New() (*Watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start inotify: %w", err)
+	}
+
+	events, errs := make(chan string), make(chan error)
+	w := &Watcher{
+		Events: events,
+		Errors: errs,
+		events: events,
+		errors: errs,
+		done:   make(chan struct{}),
+		// A non-blocking file goes through the runtime poller, so closing
+		// it stops the pending read
+		file: os.NewFile(uintptr(fd), "inotify"),
+		fd:   fd,
+		dirs: make(map[int]*watchedDir),
+	}
+
+	go w.read()
+
+	return w, nil
+}
+
+// Add watches path, a directory with its subdirectories, hidden ones
+// aside, or a single file. A file is watched through its directory so
+// editors replacing it are noticed too.
+func (w *Watcher) Add(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return w.addDir(filepath.Dir(path), false, filepath.Base(path))
+	}
+
+	return filepath.WalkDir(path, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		if current != path && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		return w.addDir(current, true)
+	})
+}
+
+func (w *Watcher) addDir(path string, recursive bool, names ...string) error {
+	wd, err := unix.InotifyAddWatch(w.fd, path, mask)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dir, ok := w.dirs[wd]
+	if !ok {
+		dir = &watchedDir{path: path, names: make(map[string]bool)}
+		w.dirs[wd] = dir
+	}
+
+	dir.recursive = dir.recursive || recursive
+	for _, name := range names {
+		dir.names[name] = true
+	}
+
+	return nil
+}
+
+// Remove stops watching path, a directory added with its subdirectories.
+// A directory also holding watched files keeps reporting them.
+func (w *Watcher) Remove(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var errs []error
+	for wd, dir := range w.dirs {
+		if !dir.recursive || (dir.path != path && !strings.HasPrefix(dir.path, path+string(filepath.Separator))) {
+			continue
+		}
+
+		if len(dir.names) > 0 {
+			dir.recursive = false
+			continue
+		}
+
+		delete(w.dirs, wd)
+
+		// A deleted directory was already dropped by inotify
+		_, err := unix.InotifyRmWatch(w.fd, uint32(wd))
+		if err != nil && !errors.Is(err, unix.EINVAL) {
+			errs = append(errs, fmt.Errorf("failed to stop watching %s: %w", dir.path, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	close(w.done)
+
+	return w.file.Close()
+}
+
+func (w *Watcher) read() {
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if errors.Is(err, os.ErrClosed) {
+			return
+		}
+
+		if err != nil {
+			w.sendError(err)
+			return
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			wd := int(int32(binary.NativeEndian.Uint32(buf[offset:])))
+			eventMask := binary.NativeEndian.Uint32(buf[offset+4:])
+			length := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+
+			nameStart := offset + unix.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[nameStart:nameStart+length]), "\x00")
+			offset = nameStart + length
+
+			w.handle(wd, eventMask, name)
+		}
+	}
+}
+
+func (w *Watcher) handle(wd int, eventMask uint32, name string) {
+	if eventMask&unix.IN_Q_OVERFLOW != 0 {
+		w.sendError(ErrOverflow)
+		return
+	}
+
+	// Add and Remove change the directories, what the event needs is read
+	// while they can't
+	w.mu.Lock()
+	dir, ok := w.dirs[wd]
+	var dirPath string
+	var recursive, watched bool
+	if ok {
+		dirPath, recursive = dir.path, dir.recursive
+		watched = recursive || dir.names[name]
+	}
+	if eventMask&unix.IN_IGNORED != 0 {
+		// The directory was removed
+		delete(w.dirs, wd)
+	}
+	w.mu.Unlock()
+
+	if !ok || name == "" || !watched {
+		return
+	}
+
+	path := filepath.Join(dirPath, name)
+
+	created := eventMask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0
+	if recursive && created && eventMask&unix.IN_ISDIR != 0 && !strings.HasPrefix(name, ".") {
+		err := w.Add(path)
+		if err != nil {
+			w.sendError(err)
+		}
+	}
+
+	select {
+	case w.events <- path:
+	case <-w.done:
+	}
+}
+
+func (w *Watcher) sendError(err error) {
+	select {
+	case w.errors <- err:
+	case <-w.done:
+	}
+}
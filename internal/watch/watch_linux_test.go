@@ -0,0 +1,120 @@
+//go:build linux
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func nextEvent(t *testing.T, w *Watcher) string {
+	t.Helper()
+
+	select {
+	case path := <-w.Events:
+		return path
+	case err := <-w.Errors:
+		t.Fatalf("watching failed: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+
+	return ""
+}
+
+func TestWatcher(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	themesDir := filepath.Join(tmpDir, "themes")
+	c.NoError(os.MkdirAll(themesDir, os.ModePerm))
+
+	configPath := filepath.Join(tmpDir, "altie.conf")
+	c.NoError(os.WriteFile(configPath, nil, 0o644))
+
+	w, err := New()
+	c.NoError(err)
+
+	defer w.Close()
+
+	c.NoError(w.Add(themesDir))
+	c.NoError(w.Add(configPath))
+
+	// Files next to a watched file are left out
+	c.NoError(os.WriteFile(filepath.Join(tmpDir, "state.json"), nil, 0o644))
+	c.NoError(os.WriteFile(configPath, []byte("[Config]"), 0o644))
+	c.Equal(configPath, nextEvent(t, w))
+
+	nord := filepath.Join(themesDir, "Nord.toml")
+	// Created, then written
+	c.NoError(os.WriteFile(nord, nil, 0o644))
+	c.Equal(nord, nextEvent(t, w))
+	c.Equal(nord, nextEvent(t, w))
+
+	// New categories are watched as they're created
+	category := filepath.Join(themesDir, "catppuccin")
+	c.NoError(os.Mkdir(category, os.ModePerm))
+	c.Equal(category, nextEvent(t, w))
+
+	mocha := filepath.Join(category, "Mocha.toml")
+	c.NoError(os.WriteFile(mocha, nil, 0o644))
+	c.Equal(mocha, nextEvent(t, w))
+	c.Equal(mocha, nextEvent(t, w))
+
+	c.NoError(os.Remove(nord))
+	c.Equal(nord, nextEvent(t, w))
+
+	// Removed directories are left out, the watched files stay
+	c.NoError(w.Remove(themesDir))
+	c.NoError(os.WriteFile(filepath.Join(category, "Latte.toml"), nil, 0o644))
+	c.NoError(os.WriteFile(configPath, nil, 0o644))
+	c.Equal(configPath, nextEvent(t, w))
+}
+
+func TestWatcherChangedWhileWatching(t *testing.T) {
+	c := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "test")
+	c.NoError(err)
+
+	defer os.RemoveAll(tmpDir)
+
+	w, err := New()
+	c.NoError(err)
+
+	defer w.Close()
+
+	c.NoError(w.Add(tmpDir))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-w.Events:
+			case <-w.Errors:
+			case <-time.After(200 * time.Millisecond):
+				return
+			}
+		}
+	}()
+
+	// Run with -race, events are handled while the directory comes and goes
+	for i := 0; i < 50; i++ {
+		c.NoError(os.WriteFile(filepath.Join(tmpDir, "Nord.toml"), nil, 0o644))
+		c.NoError(w.Add(tmpDir))
+		c.NoError(w.Add(filepath.Join(tmpDir, "Nord.toml")))
+		c.NoError(w.Remove(tmpDir))
+		c.NoError(w.Add(tmpDir))
+	}
+
+	<-done
+}
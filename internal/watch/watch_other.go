@@ -0,0 +1,25 @@
+//go:build !linux
+
+package watch
+
+// Watcher is only implemented with inotify on Linux.
+type Watcher struct {
+	Events <-chan string
+	Errors <-chan error
+}
+
+func New() (*Watcher, error) {
+	return nil, ErrUnsupported
+}
+
+func (w *Watcher) Add(path string) error {
+	return ErrUnsupported
+}
+
+func (w *Watcher) Remove(path string) error {
+	return ErrUnsupported
+}
+
+func (w *Watcher) Close() error {
+	return nil
+}